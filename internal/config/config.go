@@ -0,0 +1,275 @@
+// Package config loads sqlgen.yaml project files.
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultFile is the config file loaded from the working directory when no
+// -config flag is given.
+const DefaultFile = "sqlgen.yaml"
+
+// Config mirrors the command-line flags, plus settings that only have a
+// file form.
+type Config struct {
+	Connection Connection              `yaml:"connection"`
+	Tables     Tables                  `yaml:"tables"`
+	Output     Output                  `yaml:"output"`
+	Types      map[string]TypeOverride `yaml:"types"`
+	Decimal    string                  `yaml:"decimal"`
+	JSON       string                  `yaml:"json"`
+	Nullable   string                  `yaml:"nullable"`
+	Bool       bool                    `yaml:"bool"`
+	Tags       []Tag                   `yaml:"tags"`
+	Naming     Naming                  `yaml:"naming"`
+}
+
+// Connection selects the schema source.
+type Connection struct {
+	DSN        string   `yaml:"dsn"`
+	Host       string   `yaml:"host"`
+	Port       int      `yaml:"port"`
+	User       string   `yaml:"user"`
+	Password   string   `yaml:"password"`
+	Database   string   `yaml:"database"`
+	SchemaFile []string `yaml:"schema_file"`
+}
+
+// Tables selects the tables to generate. Include and Exclude hold patterns:
+// a glob as understood by path.Match, such as *_backup, or a regular
+// expression between slashes, such as /^(tmp|old)_/. A plain table name is
+// a glob that matches only itself.
+type Tables struct {
+	Include []string `yaml:"include"`
+	Exclude []string `yaml:"exclude"`
+	// ExcludeViews leaves out views, generating base tables only.
+	ExcludeViews bool `yaml:"exclude_views"`
+}
+
+// Output controls what is generated and where.
+type Output struct {
+	Dir       string `yaml:"dir"`
+	Package   string `yaml:"package"`
+	Force     bool   `yaml:"force"`
+	CRUD      bool   `yaml:"crud"`
+	Relations bool   `yaml:"relations"`
+	Template  string `yaml:"template"`
+	Prune     bool   `yaml:"prune"`
+}
+
+// TypeOverride maps the columns matching its key, a data type, full column
+// type or table.column pattern, to a Go type. It may be written as a plain
+// type name or as a mapping with an import path and nullable type. With
+// JSON set, the column holds the type as JSON.
+type TypeOverride struct {
+	Type     string `yaml:"type"`
+	Import   string `yaml:"import"`
+	Nullable string `yaml:"nullable"`
+	JSON     bool   `yaml:"json"`
+}
+
+// Tag adds a struct tag to every field. It may be written as a plain tag
+// key or as a mapping with settings for the tag.
+type Tag struct {
+	Key       string `yaml:"key"`
+	Case      string `yaml:"case"`
+	OmitEmpty *bool  `yaml:"omitempty"`
+	Template  string `yaml:"template"`
+}
+
+// Naming adjusts how names are converted to Go identifiers.
+type Naming struct {
+	// Initialisms are added to the built-in ones, such as ID and URL.
+	Initialisms []string `yaml:"initialisms"`
+}
+
+func (o *TypeOverride) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		o.Type = node.Value
+		return nil
+	}
+	type plain TypeOverride
+	return node.Decode((*plain)(o))
+}
+
+func (t *Tag) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		t.Key = node.Value
+		return nil
+	}
+	type plain Tag
+	return node.Decode((*plain)(t))
+}
+
+// Load reads the config file at path. Environment variables written as
+// $NAME or ${NAME} are expanded in every value; ${NAME:-default} falls back
+// to default when NAME is unset, and $$ is a literal dollar sign.
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("failed to parse config %s: %w", path, err)
+	}
+	if err := expandNode(&root); err != nil {
+		return nil, fmt.Errorf("failed to parse config %s: %w", path, err)
+	}
+
+	// Re-encode the expanded document so unknown keys are rejected.
+	expanded, err := yaml.Marshal(&root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse config %s: %w", path, err)
+	}
+	var cfg Config
+	dec := yaml.NewDecoder(bytes.NewReader(expanded))
+	dec.KnownFields(true)
+	if err := dec.Decode(&cfg); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to parse config %s: %w", path, err)
+	}
+	return &cfg, nil
+}
+
+// expandNode expands environment variables in the scalar values under node.
+// Mapping keys are left alone.
+func expandNode(node *yaml.Node) error {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		value, err := expandEnv(node.Value)
+		if err != nil {
+			return fmt.Errorf("line %d: %w", node.Line, err)
+		}
+		if value != node.Value && node.Style == 0 {
+			// Let the expanded value of an unquoted scalar resolve to
+			// its own type, so port: ${DB_PORT} is still an int.
+			node.Tag = ""
+		}
+		node.Value = value
+	case yaml.MappingNode:
+		for i := 1; i < len(node.Content); i += 2 {
+			if err := expandNode(node.Content[i]); err != nil {
+				return err
+			}
+		}
+	default:
+		for _, child := range node.Content {
+			if err := expandNode(child); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func expandEnv(s string) (string, error) {
+	var missing []string
+	value := os.Expand(s, func(name string) string {
+		if name == "$" {
+			return "$"
+		}
+		name, def, hasDefault := strings.Cut(name, ":-")
+		if v, ok := os.LookupEnv(name); ok {
+			return v
+		}
+		if !hasDefault {
+			missing = append(missing, name)
+		}
+		return def
+	})
+	if len(missing) > 0 {
+		return "", fmt.Errorf("environment variable %s is not set", strings.Join(missing, ", "))
+	}
+	return value, nil
+}
+
+// Filter returns the names matching an include pattern, or every name if
+// there are none, and no exclude pattern, in their original order.
+func (t Tables) Filter(names []string) ([]string, error) {
+	include, err := compilePatterns(t.Include)
+	if err != nil {
+		return nil, fmt.Errorf("include: %w", err)
+	}
+	exclude, err := compilePatterns(t.Exclude)
+	if err != nil {
+		return nil, fmt.Errorf("exclude: %w", err)
+	}
+
+	var result []string
+	for _, name := range names {
+		if len(include) > 0 && !matchAny(include, name) {
+			continue
+		}
+		if !matchAny(exclude, name) {
+			result = append(result, name)
+		}
+	}
+	return result, nil
+}
+
+// compilePatterns returns a function reporting whether a name matches each
+// of patterns.
+func compilePatterns(patterns []string) ([]func(string) bool, error) {
+	matchers := make([]func(string) bool, 0, len(patterns))
+	for _, pattern := range patterns {
+		if len(pattern) > 1 && strings.HasPrefix(pattern, "/") && strings.HasSuffix(pattern, "/") {
+			re, err := regexp.Compile(pattern[1 : len(pattern)-1])
+			if err != nil {
+				return nil, fmt.Errorf("invalid pattern %s: %w", pattern, err)
+			}
+			matchers = append(matchers, re.MatchString)
+			continue
+		}
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+		}
+		matchers = append(matchers, func(name string) bool {
+			ok, _ := path.Match(pattern, name)
+			return ok
+		})
+	}
+	return matchers, nil
+}
+
+func matchAny(matchers []func(string) bool, name string) bool {
+	for _, match := range matchers {
+		if match(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// SplitPatterns splits a comma-separated list of table patterns, as given on
+// the command line. Commas inside a regular expression, as in /^t{1,3}$/,
+// do not split it.
+func SplitPatterns(s string) []string {
+	var patterns []string
+	var current []string
+	for _, part := range strings.Split(s, ",") {
+		current = append(current, part)
+		pattern := strings.Join(current, ",")
+		trimmed := strings.TrimSpace(pattern)
+		if strings.HasPrefix(trimmed, "/") && (len(trimmed) == 1 || !strings.HasSuffix(trimmed, "/")) {
+			continue
+		}
+		current = nil
+		if trimmed != "" {
+			patterns = append(patterns, trimmed)
+		}
+	}
+	if rest := strings.TrimSpace(strings.Join(current, ",")); rest != "" {
+		patterns = append(patterns, rest)
+	}
+	return patterns
+}
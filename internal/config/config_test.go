@@ -0,0 +1,209 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	tmpDir, err := os.MkdirTemp("", "sqlgen_test")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	t.Cleanup(func() { os.RemoveAll(tmpDir) })
+
+	path := filepath.Join(tmpDir, DefaultFile)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	t.Setenv("SQLGEN_TEST_PORT", "3307")
+	t.Setenv("SQLGEN_TEST_PASSWORD", "p@ss: #word")
+
+	path := writeConfig(t, `
+connection:
+  host: ${SQLGEN_TEST_HOST:-db.internal}
+  port: ${SQLGEN_TEST_PORT}
+  user: app
+  password: $SQLGEN_TEST_PASSWORD
+  database: "shop_$$"
+  schema_file: [schema.sql, migrations]
+tables:
+  include: [users, orders]
+  exclude: [schema_migrations]
+  exclude_views: true
+output:
+  dir: ./models
+  package: db
+  force: true
+  crud: true
+  relations: true
+  template: ./templates
+  prune: true
+types:
+  json: json.RawMessage
+  decimal:
+    type: decimal.Decimal
+    import: github.com/shopspring/decimal
+    nullable: decimal.NullDecimal
+  users.settings:
+    type: settings.Settings
+    import: example.com/app/settings
+    json: true
+decimal: shopspring
+json: raw
+nullable: sql
+bool: true
+tags:
+  - json
+  - key: gorm
+  - key: yaml
+    case: camel
+    omitempty: false
+  - key: xml
+    template: "{{.Name}},attr"
+naming:
+  initialisms: [ID, URL]
+`)
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	want := &Config{
+		Connection: Connection{
+			Host:       "db.internal",
+			Port:       3307,
+			User:       "app",
+			Password:   "p@ss: #word",
+			Database:   "shop_$",
+			SchemaFile: []string{"schema.sql", "migrations"},
+		},
+		Tables: Tables{Include: []string{"users", "orders"}, Exclude: []string{"schema_migrations"}, ExcludeViews: true},
+		Output: Output{Dir: "./models", Package: "db", Force: true, CRUD: true, Relations: true, Template: "./templates", Prune: true},
+		Types: map[string]TypeOverride{
+			"json":           {Type: "json.RawMessage"},
+			"decimal":        {Type: "decimal.Decimal", Import: "github.com/shopspring/decimal", Nullable: "decimal.NullDecimal"},
+			"users.settings": {Type: "settings.Settings", Import: "example.com/app/settings", JSON: true},
+		},
+		Decimal:  "shopspring",
+		JSON:     "raw",
+		Nullable: "sql",
+		Bool:     true,
+		Tags: []Tag{
+			{Key: "json"},
+			{Key: "gorm"},
+			{Key: "yaml", Case: "camel", OmitEmpty: new(bool)},
+			{Key: "xml", Template: "{{.Name}},attr"},
+		},
+		Naming: Naming{Initialisms: []string{"ID", "URL"}},
+	}
+	if !reflect.DeepEqual(cfg, want) {
+		t.Errorf("Load() = %+v, want %+v", cfg, want)
+	}
+}
+
+func TestLoadEmpty(t *testing.T) {
+	cfg, err := Load(writeConfig(t, "# nothing configured\n"))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if !reflect.DeepEqual(cfg, &Config{}) {
+		t.Errorf("Load() = %+v, want empty config", cfg)
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{"unset variable", "connection:\n  password: ${SQLGEN_TEST_UNSET}\n"},
+		{"unknown key", "connection:\n  hostname: localhost\n"},
+		{"wrong type", "connection:\n  port: three\n"},
+		{"invalid yaml", "connection: [\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Load(writeConfig(t, tt.content)); err == nil {
+				t.Error("Load() error = nil, want error")
+			}
+		})
+	}
+
+	if _, err := Load(filepath.Join(os.TempDir(), "sqlgen_missing.yaml")); err == nil {
+		t.Error("Load() with missing file error = nil, want error")
+	}
+}
+
+func TestTablesFilter(t *testing.T) {
+	names := []string{"orders", "orders_backup", "schema_migrations", "tmp_users", "users"}
+
+	tests := []struct {
+		name   string
+		tables Tables
+		want   []string
+	}{
+		{"no lists", Tables{}, names},
+		{"exclude", Tables{Exclude: []string{"schema_migrations"}}, []string{"orders", "orders_backup", "tmp_users", "users"}},
+		{"include", Tables{Include: []string{"users", "missing"}}, []string{"users"}},
+		{"include and exclude", Tables{Include: []string{"users", "orders"}, Exclude: []string{"orders"}}, []string{"users"}},
+		{"glob", Tables{Exclude: []string{"*_backup", "schema_*"}}, []string{"orders", "tmp_users", "users"}},
+		{"glob include", Tables{Include: []string{"*users"}}, []string{"tmp_users", "users"}},
+		{"regex", Tables{Exclude: []string{"/^tmp_|_backup$/"}}, []string{"orders", "schema_migrations", "users"}},
+		{"regex is unanchored", Tables{Include: []string{"/migration/"}}, []string{"schema_migrations"}},
+		{"glob and regex", Tables{Include: []string{"/^orders/", "users"}, Exclude: []string{"*_backup"}}, []string{"orders", "users"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.tables.Filter(names)
+			if err != nil {
+				t.Fatalf("Filter() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Filter() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTablesFilterInvalidPattern(t *testing.T) {
+	for _, tables := range []Tables{
+		{Include: []string{"/(/"}},
+		{Exclude: []string{"[users"}},
+	} {
+		if _, err := tables.Filter([]string{"users"}); err == nil {
+			t.Errorf("Filter() with %+v error = nil, want error", tables)
+		}
+	}
+}
+
+func TestSplitPatterns(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{"", nil},
+		{"users", []string{"users"}},
+		{"users, orders,,", []string{"users", "orders"}},
+		{"*_backup,/^tmp_/", []string{"*_backup", "/^tmp_/"}},
+		{"/^t{1,3}$/,users", []string{"/^t{1,3}$/", "users"}},
+		{"/", []string{"/"}},
+		{"/a,b", []string{"/a,b"}},
+	}
+
+	for _, tt := range tests {
+		if got := SplitPatterns(tt.in); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("SplitPatterns(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
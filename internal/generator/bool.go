@@ -0,0 +1,72 @@
+package generator
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/ttaatoo/sqlgen/internal/schema"
+)
+
+// bitType is the type of BIT(1) columns mapped to booleans, declared in the
+// shared file. The MySQL driver returns BIT values as bytes, which
+// database/sql cannot scan into a bool.
+const bitType = "Bit"
+
+// boolType returns the Go type of col of table if WithBool maps it to a
+// boolean: bool for TINYINT(1) and Bit for BIT(1). It returns "" for other
+// columns, for tables of dialects other than MySQL, or if the mapping is
+// off.
+func (g *Generator) boolType(table *schema.Table, col schema.Column) string {
+	if !g.bools || (table.Dialect != "" && table.Dialect != schema.MySQL) {
+		return ""
+	}
+	columnType := strings.ToLower(col.ColumnType)
+	switch {
+	case strings.HasPrefix(columnType, "tinyint(1)"):
+		return "bool"
+	case columnType == "bit(1)":
+		return bitType
+	}
+	return ""
+}
+
+// hasBit reports whether a column of table has the Bit type, needing the
+// shared file. Columns with a type override do not.
+func (g *Generator) hasBit(table *schema.Table) bool {
+	for _, col := range table.Columns {
+		if _, ok := g.override(table, col); !ok && g.boolType(table, col) == bitType {
+			return true
+		}
+	}
+	return false
+}
+
+// generateBit writes the Bit type to the shared file.
+func generateBit(buf *bytes.Buffer) {
+	buf.WriteString("\n// Bit is a BIT(1) column as a bool. It scans the single byte the MySQL\n")
+	buf.WriteString("// driver returns for BIT columns.\n")
+	buf.WriteString("type Bit bool\n\n")
+
+	buf.WriteString("// Scan implements sql.Scanner.\n")
+	buf.WriteString("func (b *Bit) Scan(src any) error {\n")
+	buf.WriteString("\tswitch value := src.(type) {\n")
+	buf.WriteString("\tcase []byte:\n")
+	buf.WriteString("\t\tif len(value) != 1 {\n")
+	buf.WriteString("\t\t\treturn fmt.Errorf(\"cannot scan %d bytes into Bit\", len(value))\n")
+	buf.WriteString("\t\t}\n")
+	buf.WriteString("\t\t*b = value[0] != 0\n")
+	buf.WriteString("\tcase int64:\n")
+	buf.WriteString("\t\t*b = value != 0\n")
+	buf.WriteString("\tcase bool:\n")
+	buf.WriteString("\t\t*b = Bit(value)\n")
+	buf.WriteString("\tdefault:\n")
+	buf.WriteString("\t\treturn fmt.Errorf(\"cannot scan %T into Bit\", src)\n")
+	buf.WriteString("\t}\n")
+	buf.WriteString("\treturn nil\n")
+	buf.WriteString("}\n\n")
+
+	buf.WriteString("// Value implements driver.Valuer.\n")
+	buf.WriteString("func (b Bit) Value() (driver.Value, error) {\n")
+	buf.WriteString("\treturn bool(b), nil\n")
+	buf.WriteString("}\n")
+}
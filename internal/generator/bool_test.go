@@ -0,0 +1,93 @@
+package generator
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/ttaatoo/sqlgen/internal/schema"
+)
+
+func TestBoolGoType(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+		want []string
+	}{
+		{"off", nil, []string{"int32", "int8", "*uint8", "[]byte", "[]byte", "int8", "[]byte"}},
+		{"pointer", []Option{WithBool(true)}, []string{"int32", "bool", "*bool", "Bit", "*Bit", "int8", "[]byte"}},
+		{"sql", []Option{WithBool(true), WithNullable(NullSQL)}, []string{"int32", "bool", "sql.NullBool", "Bit", "sql.Null[Bit]", "int8", "[]byte"}},
+		{"overridden", []Option{WithBool(true), WithTypeOverrides(map[string]TypeOverride{"flags.active": {Type: "int8"}})}, []string{"int32", "int8", "*bool", "Bit", "*Bit", "int8", "[]byte"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gen := New("models", "/tmp/output", tt.opts...)
+			table := testTable("bool")
+			for i, col := range table.Columns {
+				if got := gen.goType(table, col); got != tt.want[i] {
+					t.Errorf("goType(%s) = %q, want %q", col.Name, got, tt.want[i])
+				}
+			}
+		})
+	}
+
+	// Only MySQL has TINYINT(1) booleans and BIT values returned as bytes.
+	gen := New("models", "/tmp/output", WithBool(true))
+	table := testTable("bool")
+	table.Dialect = schema.SQLite
+	if got := gen.goType(table, table.Columns[1]); got == "bool" {
+		t.Errorf("goType() of sqlite tinyint(1) = %q, want the sqlite mapping", got)
+	}
+}
+
+func TestGenerateBitSharedFile(t *testing.T) {
+	content := sharedFile(t, testTable("bool"), WithBool(true))
+	for _, want := range []string{
+		"import (\n\t\"database/sql/driver\"\n\t\"fmt\"\n)",
+		"type Bit bool",
+		"func (b *Bit) Scan(src any) error {",
+		"func (b Bit) Value() (driver.Value, error) {",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("shared file should contain %q\n%s", want, content)
+		}
+	}
+	if strings.Contains(content, "DBTX") {
+		t.Errorf("shared file should not contain query helpers without -crud or -relations\n%s", content)
+	}
+}
+
+func TestBitScanValue(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "sqlgen_test")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	if err := New("models", tmpDir, WithCRUD(true), WithBool(true)).Generate(testTable("bool")); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	runGenerated(t, tmpDir, `package models
+
+import "testing"
+
+func TestBit(t *testing.T) {
+	var b Bit
+	for _, src := range []any{[]byte{1}, int64(1)} {
+		if err := b.Scan(src); err != nil || !b {
+			t.Errorf("Scan(%v) = %v, %v, want true", src, b, err)
+		}
+	}
+	if err := b.Scan([]byte{0}); err != nil || b {
+		t.Errorf("Scan(0) = %v, %v, want false", b, err)
+	}
+	if err := b.Scan([]byte{0, 1}); err == nil {
+		t.Error("Scan() of two bytes should fail")
+	}
+	if v, err := Bit(true).Value(); v != true || err != nil {
+		t.Errorf("Value() = %#v, %v, want true", v, err)
+	}
+}
+`)
+}
@@ -0,0 +1,270 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"go/token"
+	"strconv"
+	"strings"
+
+	"github.com/ttaatoo/sqlgen/internal/schema"
+)
+
+// CRUD code is generated per table against the shared DBTX interface:
+//
+//	func (u *Users) Insert(ctx context.Context, db DBTX) error
+//	func GetUsers(ctx context.Context, db DBTX, id int64) (*Users, error)
+//	func (u *Users) Update(ctx context.Context, db DBTX) error
+//	func (u *Users) Delete(ctx context.Context, db DBTX) error
+//
+// Get, Update and Delete need a primary key and are omitted without one.
+// Views are read-only and only get Get.
+
+// bindParam returns the nth (1-based) bind parameter for the dialect.
+func bindParam(dialect schema.Dialect, n int) string {
+	if dialect == schema.Postgres {
+		return "$" + strconv.Itoa(n)
+	}
+	return "?"
+}
+
+// isAutoIncrement reports whether the database assigns col's value on insert.
+func isAutoIncrement(col schema.Column) bool {
+	return strings.Contains(strings.ToLower(col.Extra), "auto_increment")
+}
+
+// isGeneratedColumn reports whether col is computed from other columns and
+// cannot be written.
+func isGeneratedColumn(col schema.Column) bool {
+	extra := strings.ToUpper(col.Extra)
+	return strings.Contains(extra, "VIRTUAL GENERATED") || strings.Contains(extra, "STORED GENERATED")
+}
+
+// isIntegerType reports whether goType is a built-in integer type.
+func isIntegerType(goType string) bool {
+	switch goType {
+	case "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64":
+		return true
+	}
+	return false
+}
+
+// paramName returns the Go parameter name for a column, avoiding keywords
+// and the names used by the generated function bodies.
+func (g *Generator) paramName(column string) string {
+	name := g.camel(column)
+	if name == "" {
+		return "key"
+	}
+	// A leading initialism is lowered as a whole, as in id or urlPath.
+	first, _, _ := strings.Cut(strings.TrimLeft(column, "_"), "_")
+	if head := strings.TrimSuffix(name[:len(first)], "s"); head != "" && head == strings.ToUpper(head) {
+		name = strings.ToLower(name[:len(first)]) + name[len(first):]
+	} else {
+		name = strings.ToLower(name[:1]) + name[1:]
+	}
+	switch name {
+	case "ctx", "db", "query", "row", "err":
+		return name + "_"
+	}
+	if token.IsKeyword(name) {
+		return name + "_"
+	}
+	return name
+}
+
+// whereClause returns "a = ? AND b = ?" for cols, numbering parameters
+// from start.
+func whereClause(dialect schema.Dialect, cols []schema.Column, start int) string {
+	conds := make([]string, len(cols))
+	for i, col := range cols {
+		conds[i] = quoteIdent(dialect, col.Name) + " = " + bindParam(dialect, start+i)
+	}
+	return strings.Join(conds, " AND ")
+}
+
+// fieldArgs returns the fields of cols on the struct variable v, as query
+// arguments.
+func (g *Generator) fieldArgs(cols []schema.Column, v string) string {
+	args := make([]string, len(cols))
+	for i, col := range cols {
+		args[i] = v + "." + g.camel(col.Name)
+	}
+	return strings.Join(args, ", ")
+}
+
+// hasCRUD reports whether any CRUD code is generated for table.
+func (g *Generator) hasCRUD(table *schema.Table) bool {
+	return g.crud && (!table.IsView() || len(table.PrimaryKey()) > 0)
+}
+
+func (g *Generator) generateCRUD(buf *bytes.Buffer, table *schema.Table) {
+	if !table.IsView() {
+		g.generateInsert(buf, table)
+	}
+
+	pk := table.PrimaryKey()
+	if len(pk) == 0 {
+		return
+	}
+	g.generateGet(buf, table, pk)
+	if table.IsView() {
+		return
+	}
+	g.generateUpdate(buf, table, pk)
+	g.generateDelete(buf, table, pk)
+}
+
+func (g *Generator) generateInsert(buf *bytes.Buffer, table *schema.Table) {
+	structName := g.camel(table.Name)
+	recv := receiverName(structName)
+
+	var cols []schema.Column
+	var autoInc *schema.Column
+	for i, col := range table.Columns {
+		if isAutoIncrement(col) && autoInc == nil {
+			autoInc = &table.Columns[i]
+			continue
+		}
+		if isGeneratedColumn(col) {
+			continue
+		}
+		cols = append(cols, col)
+	}
+
+	query := "INSERT INTO " + quoteIdent(table.Dialect, table.Name)
+	if len(cols) > 0 {
+		names := make([]string, len(cols))
+		params := make([]string, len(cols))
+		for i, col := range cols {
+			names[i] = quoteIdent(table.Dialect, col.Name)
+			params[i] = bindParam(table.Dialect, i+1)
+		}
+		query += fmt.Sprintf(" (%s) VALUES (%s)", strings.Join(names, ", "), strings.Join(params, ", "))
+	} else if table.Dialect == schema.MySQL || table.Dialect == "" {
+		query += " () VALUES ()"
+	} else {
+		query += " DEFAULT VALUES"
+	}
+
+	args := ""
+	if len(cols) > 0 {
+		args = ", " + g.fieldArgs(cols, recv)
+	}
+
+	buf.WriteString(fmt.Sprintf("\n// Insert inserts the row into %s", table.Name))
+	if autoInc != nil {
+		buf.WriteString(fmt.Sprintf(" and sets %s to the\n// value assigned by the database", g.camel(autoInc.Name)))
+	}
+	buf.WriteString(".\n")
+	buf.WriteString(fmt.Sprintf("func (%s *%s) Insert(ctx context.Context, db DBTX) error {\n", recv, structName))
+
+	switch {
+	case autoInc != nil && table.Dialect == schema.Postgres:
+		// lib/pq does not support LastInsertId, so read the key back with
+		// RETURNING instead.
+		query += " RETURNING " + quoteIdent(table.Dialect, autoInc.Name)
+		buf.WriteString(fmt.Sprintf("\tquery := %s\n", strconv.Quote(query)))
+		buf.WriteString(fmt.Sprintf("\treturn db.QueryRowContext(ctx, query%s).Scan(&%s.%s)\n",
+			args, recv, g.camel(autoInc.Name)))
+	case autoInc != nil && isIntegerType(g.baseType(table, *autoInc)):
+		field := recv + "." + g.camel(autoInc.Name)
+		typ := g.baseType(table, *autoInc)
+		buf.WriteString(fmt.Sprintf("\tquery := %s\n", strconv.Quote(query)))
+		buf.WriteString(fmt.Sprintf("\tres, err := db.ExecContext(ctx, query%s)\n", args))
+		buf.WriteString("\tif err != nil {\n\t\treturn err\n\t}\n")
+		buf.WriteString("\tid, err := res.LastInsertId()\n")
+		buf.WriteString("\tif err != nil {\n\t\treturn err\n\t}\n")
+		value := "id"
+		if typ != "int64" {
+			value = typ + "(id)"
+		}
+		if fieldType := g.goType(table, *autoInc); autoInc.IsNullable && strings.HasPrefix(fieldType, "*") {
+			buf.WriteString(fmt.Sprintf("\tkey := %s\n\t%s = &key\n", value, field))
+		} else if autoInc.IsNullable {
+			buf.WriteString(fmt.Sprintf("\t%s = %s\n", field, validValue(fieldType, typ, value)))
+		} else {
+			buf.WriteString(fmt.Sprintf("\t%s = %s\n", field, value))
+		}
+		buf.WriteString("\treturn nil\n")
+	default:
+		buf.WriteString(fmt.Sprintf("\tquery := %s\n", strconv.Quote(query)))
+		buf.WriteString(fmt.Sprintf("\t_, err := db.ExecContext(ctx, query%s)\n", args))
+		buf.WriteString("\treturn err\n")
+	}
+	buf.WriteString("}\n")
+}
+
+func (g *Generator) generateGet(buf *bytes.Buffer, table *schema.Table, pk []schema.Column) {
+	structName := g.camel(table.Name)
+
+	params := make([]string, len(pk))
+	args := make([]string, len(pk))
+	for i, col := range pk {
+		name := g.paramName(col.Name)
+		params[i] = name + " " + g.baseType(table, col)
+		args[i] = name
+	}
+
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE %s", columnList(table),
+		quoteIdent(table.Dialect, table.Name), whereClause(table.Dialect, pk, 1))
+
+	buf.WriteString(fmt.Sprintf("\n// Get%s returns the %s row with the given primary key, or\n", structName, table.Name))
+	buf.WriteString("// sql.ErrNoRows if there is none.\n")
+	buf.WriteString(fmt.Sprintf("func Get%s(ctx context.Context, db DBTX, %s) (*%s, error) {\n",
+		structName, strings.Join(params, ", "), structName))
+	buf.WriteString(fmt.Sprintf("\tquery := %s\n", strconv.Quote(query)))
+	buf.WriteString(fmt.Sprintf("\tvar row %s\n", structName))
+	buf.WriteString(fmt.Sprintf("\tif err := db.QueryRowContext(ctx, query, %s).Scan(%s); err != nil {\n",
+		strings.Join(args, ", "), g.scanArgs(table, "row")))
+	buf.WriteString("\t\treturn nil, err\n\t}\n")
+	buf.WriteString("\treturn &row, nil\n")
+	buf.WriteString("}\n")
+}
+
+func (g *Generator) generateUpdate(buf *bytes.Buffer, table *schema.Table, pk []schema.Column) {
+	structName := g.camel(table.Name)
+	recv := receiverName(structName)
+
+	isKey := make(map[string]bool)
+	for _, col := range pk {
+		isKey[col.Name] = true
+	}
+	var cols []schema.Column
+	for _, col := range table.Columns {
+		if !isKey[col.Name] && !isGeneratedColumn(col) {
+			cols = append(cols, col)
+		}
+	}
+	if len(cols) == 0 {
+		return
+	}
+
+	sets := make([]string, len(cols))
+	for i, col := range cols {
+		sets[i] = quoteIdent(table.Dialect, col.Name) + " = " + bindParam(table.Dialect, i+1)
+	}
+	query := fmt.Sprintf("UPDATE %s SET %s WHERE %s", quoteIdent(table.Dialect, table.Name),
+		strings.Join(sets, ", "), whereClause(table.Dialect, pk, len(cols)+1))
+
+	buf.WriteString(fmt.Sprintf("\n// Update writes every column of the row to %s, matching it by primary key.\n", table.Name))
+	buf.WriteString(fmt.Sprintf("func (%s *%s) Update(ctx context.Context, db DBTX) error {\n", recv, structName))
+	buf.WriteString(fmt.Sprintf("\tquery := %s\n", strconv.Quote(query)))
+	buf.WriteString(fmt.Sprintf("\t_, err := db.ExecContext(ctx, query, %s, %s)\n", g.fieldArgs(cols, recv), g.fieldArgs(pk, recv)))
+	buf.WriteString("\treturn err\n")
+	buf.WriteString("}\n")
+}
+
+func (g *Generator) generateDelete(buf *bytes.Buffer, table *schema.Table, pk []schema.Column) {
+	structName := g.camel(table.Name)
+	recv := receiverName(structName)
+
+	query := fmt.Sprintf("DELETE FROM %s WHERE %s", quoteIdent(table.Dialect, table.Name), whereClause(table.Dialect, pk, 1))
+
+	buf.WriteString(fmt.Sprintf("\n// Delete deletes the row from %s by primary key.\n", table.Name))
+	buf.WriteString(fmt.Sprintf("func (%s *%s) Delete(ctx context.Context, db DBTX) error {\n", recv, structName))
+	buf.WriteString(fmt.Sprintf("\tquery := %s\n", strconv.Quote(query)))
+	buf.WriteString(fmt.Sprintf("\t_, err := db.ExecContext(ctx, query, %s)\n", g.fieldArgs(pk, recv)))
+	buf.WriteString("\treturn err\n")
+	buf.WriteString("}\n")
+}
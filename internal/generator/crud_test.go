@@ -0,0 +1,195 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ttaatoo/sqlgen/internal/schema"
+)
+
+func TestGenerateCRUD(t *testing.T) {
+	tests := []struct {
+		name    string
+		dialect schema.Dialect
+		want    []string
+	}{
+		{
+			name:    "mysql",
+			dialect: schema.MySQL,
+			want: []string{
+				"func (u *Users) Insert(ctx context.Context, db DBTX) error {",
+				"\"INSERT INTO `users` (`name`, `type`) VALUES (?, ?)\"",
+				"res, err := db.ExecContext(ctx, query, u.Name, u.Type)",
+				"id, err := res.LastInsertId()",
+				"u.ID = uint64(id)",
+				"func GetUsers(ctx context.Context, db DBTX, id uint64) (*Users, error) {",
+				"\"SELECT `id`, `name`, `type`, `full_name` FROM `users` WHERE `id` = ?\"",
+				"Scan(&row.ID, &row.Name, &row.Type, &row.FullName)",
+				"func (u *Users) Update(ctx context.Context, db DBTX) error {",
+				"\"UPDATE `users` SET `name` = ?, `type` = ? WHERE `id` = ?\"",
+				"db.ExecContext(ctx, query, u.Name, u.Type, u.ID)",
+				"func (u *Users) Delete(ctx context.Context, db DBTX) error {",
+				"\"DELETE FROM `users` WHERE `id` = ?\"",
+			},
+		},
+		{
+			name:    "postgres",
+			dialect: schema.Postgres,
+			want: []string{
+				`"INSERT INTO \"users\" (\"name\", \"type\") VALUES ($1, $2) RETURNING \"id\""`,
+				"return db.QueryRowContext(ctx, query, u.Name, u.Type).Scan(&u.ID)",
+				`"UPDATE \"users\" SET \"name\" = $1, \"type\" = $2 WHERE \"id\" = $3"`,
+				`"DELETE FROM \"users\" WHERE \"id\" = $1"`,
+			},
+		},
+		{
+			name:    "sqlite",
+			dialect: schema.SQLite,
+			want: []string{
+				`"INSERT INTO \"users\" (\"name\", \"type\") VALUES (?, ?)"`,
+				"id, err := res.LastInsertId()",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gen := New("models", "/tmp/output", WithCRUD(true))
+			table := testTable("crud")
+			table.Dialect = tt.dialect
+			code := mustRender(t, gen, table)
+			for _, want := range tt.want {
+				if !strings.Contains(code, want) {
+					t.Errorf("generated code should contain %q\n%s", want, code)
+				}
+			}
+			if !strings.Contains(code, `"context"`) {
+				t.Error("generated code should import context")
+			}
+		})
+	}
+}
+
+func TestGenerateCRUDKeys(t *testing.T) {
+	gen := New("models", "/tmp/output", WithCRUD(true))
+
+	noKey := &schema.Table{
+		Name:    "logs",
+		Columns: []schema.Column{{Name: "msg", DataType: "text", IsNullable: true}},
+	}
+	code := mustRender(t, gen, noKey)
+	if !strings.Contains(code, "func (l *Logs) Insert(") {
+		t.Errorf("table without primary key should have Insert\n%s", code)
+	}
+	for _, unwanted := range []string{"GetLogs", "Update(", "Delete("} {
+		if strings.Contains(code, unwanted) {
+			t.Errorf("table without primary key should not contain %q\n%s", unwanted, code)
+		}
+	}
+
+	composite := &schema.Table{
+		Name: "post_tags",
+		Columns: []schema.Column{
+			{Name: "post_id", DataType: "int"},
+			{Name: "tag", DataType: "varchar"},
+		},
+		Indexes: []schema.Index{{Name: "PRIMARY", Columns: []string{"tag", "post_id"}, Unique: true, Primary: true}},
+	}
+	code = mustRender(t, gen, composite)
+	for _, want := range []string{
+		"func GetPostTags(ctx context.Context, db DBTX, tag string, postID int32) (*PostTags, error) {",
+		"WHERE `tag` = ? AND `post_id` = ?",
+		"db.ExecContext(ctx, query, p.Tag, p.PostID)",
+	} {
+		if !strings.Contains(code, want) {
+			t.Errorf("generated code should contain %q\n%s", want, code)
+		}
+	}
+	if strings.Contains(code, "Update(") {
+		t.Errorf("table with only key columns should not have Update\n%s", code)
+	}
+
+	counters := &schema.Table{
+		Name:    "counters",
+		Columns: []schema.Column{{Name: "id", DataType: "int", ColumnKey: "PRI", Extra: "auto_increment"}},
+	}
+	code = mustRender(t, gen, counters)
+	if want := "\"INSERT INTO `counters` () VALUES ()\""; !strings.Contains(code, want) {
+		t.Errorf("generated code should contain %q\n%s", want, code)
+	}
+	counters.Dialect = schema.SQLite
+	code = mustRender(t, gen, counters)
+	if want := `"INSERT INTO \"counters\" DEFAULT VALUES"`; !strings.Contains(code, want) {
+		t.Errorf("generated code should contain %q\n%s", want, code)
+	}
+}
+
+func TestParamName(t *testing.T) {
+	tests := []struct {
+		column string
+		want   string
+	}{
+		{"id", "id"},
+		{"user_id", "userID"},
+		{"url_path", "urlPath"},
+		{"ids", "ids"},
+		{"type", "type_"},
+		{"db", "db_"},
+		{"Err", "err_"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.column, func(t *testing.T) {
+			if got := New("models", "/tmp/output").paramName(tt.column); got != tt.want {
+				t.Errorf("paramName(%q) = %q, want %q", tt.column, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerateCRUDView(t *testing.T) {
+	gen := New("models", "/tmp/output", WithCRUD(true))
+
+	view := testTable("view")
+	code := mustRender(t, gen, view)
+	for _, unwanted := range []string{"Insert(", "GetActiveUsers", "Update(", "Delete(", "\"context\""} {
+		if strings.Contains(code, unwanted) {
+			t.Errorf("view should not contain %q\n%s", unwanted, code)
+		}
+	}
+
+	// A snapshot may declare a key on a view, which is enough to read it.
+	view.Indexes = []schema.Index{{Name: "PRIMARY", Columns: []string{"id"}, Unique: true, Primary: true}}
+	code = mustRender(t, gen, view)
+	if !strings.Contains(code, "func GetActiveUsers(ctx context.Context, db DBTX, id int64) (*ActiveUsers, error) {") {
+		t.Errorf("view with a primary key should have Get\n%s", code)
+	}
+	for _, unwanted := range []string{"Insert(", "Update(", "Delete("} {
+		if strings.Contains(code, unwanted) {
+			t.Errorf("view should not contain %q\n%s", unwanted, code)
+		}
+	}
+}
+
+func TestGenerateRejectsSharedFilename(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "sqlgen_test")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	gen := New("models", tmpDir, WithCRUD(true))
+	if err := gen.Generate(testTable("crud")); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	table := &schema.Table{Name: "sqlgen", Columns: []schema.Column{{Name: "id", DataType: "int"}}}
+	if err := gen.Generate(table); err == nil {
+		t.Fatal("Generate() of table sqlgen should fail")
+	}
+	content, err := os.ReadFile(filepath.Join(tmpDir, sharedFilename))
+	if err != nil || !strings.Contains(string(content), "type DBTX interface") {
+		t.Errorf("shared file should be kept, got %v\n%s", err, content)
+	}
+}
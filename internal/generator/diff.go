@@ -0,0 +1,209 @@
+package generator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// diffContext is the number of unchanged lines shown around each change.
+const diffContext = 3
+
+// lineEdit is one line of a diff: ' ' for a line in both files, '-' for a
+// line only in the old file and '+' for a line only in the new one.
+type lineEdit struct {
+	op   byte
+	line string
+}
+
+// unifiedDiff returns the differences between old and new in unified diff
+// format, or "" if they are equal.
+func unifiedDiff(oldName, newName string, old, new []byte) string {
+	edits := diffLines(splitLines(string(old)), splitLines(string(new)))
+
+	// oldLines[i] and newLines[i] count the lines of each file before
+	// edits[i].
+	oldLines := make([]int, len(edits)+1)
+	newLines := make([]int, len(edits)+1)
+	var changes []int
+	for i, e := range edits {
+		oldLines[i+1], newLines[i+1] = oldLines[i], newLines[i]
+		if e.op != '+' {
+			oldLines[i+1]++
+		}
+		if e.op != '-' {
+			newLines[i+1]++
+		}
+		if e.op != ' ' {
+			changes = append(changes, i)
+		}
+	}
+	if len(changes) == 0 {
+		return ""
+	}
+
+	var buf strings.Builder
+	fmt.Fprintf(&buf, "--- %s\n+++ %s\n", oldName, newName)
+	for len(changes) > 0 {
+		// Changes whose context would overlap share a hunk.
+		last := 0
+		for last+1 < len(changes) && changes[last+1]-changes[last] <= 2*diffContext+1 {
+			last++
+		}
+		start := max(changes[0]-diffContext, 0)
+		end := min(changes[last]+1+diffContext, len(edits))
+		changes = changes[last+1:]
+
+		fmt.Fprintf(&buf, "@@ -%s +%s @@\n",
+			hunkRange(oldLines[start]+1, oldLines[end]-oldLines[start]),
+			hunkRange(newLines[start]+1, newLines[end]-newLines[start]))
+		for _, e := range edits[start:end] {
+			fmt.Fprintf(&buf, "%c%s\n", e.op, e.line)
+		}
+	}
+	return buf.String()
+}
+
+// hunkRange formats the line range of a hunk as diff(1) does: an empty
+// range is numbered after the line it follows, and a count of one is
+// implied.
+func hunkRange(start, count int) string {
+	switch count {
+	case 0:
+		return fmt.Sprintf("%d,0", start-1)
+	case 1:
+		return fmt.Sprintf("%d", start)
+	default:
+		return fmt.Sprintf("%d,%d", start, count)
+	}
+}
+
+// splitLines splits s into lines. A last line without a newline is marked
+// as diff(1) does, so that a file differing only in its final newline still
+// shows a change.
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	lines := strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+	if !strings.HasSuffix(s, "\n") {
+		lines[len(lines)-1] += "\n\\ No newline at end of file"
+	}
+	return lines
+}
+
+// diffLines returns a shortest edit script turning a into b, using the
+// linear space variant of Myers' O((N+M)D) algorithm, so that a rewritten
+// file does not need memory growing with the square of its length. Within
+// a run of changed lines, deletions come before insertions.
+func diffLines(a, b []string) []lineEdit {
+	var edits []lineEdit
+	diffRange(a, b, &edits)
+
+	for start := 0; start < len(edits); {
+		if edits[start].op == ' ' {
+			start++
+			continue
+		}
+		end := start
+		for end < len(edits) && edits[end].op != ' ' {
+			end++
+		}
+		run := edits[start:end]
+		sort.SliceStable(run, func(i, j int) bool { return run[i].op == '-' && run[j].op == '+' })
+		start = end
+	}
+	return edits
+}
+
+// diffRange appends the edits turning a into b to edits, splitting the
+// problem at the middle snake of a shortest edit script.
+func diffRange(a, b []string, edits *[]lineEdit) {
+	for len(a) > 0 && len(b) > 0 && a[0] == b[0] {
+		*edits = append(*edits, lineEdit{' ', a[0]})
+		a, b = a[1:], b[1:]
+	}
+	suffix := 0
+	for suffix < len(a) && suffix < len(b) && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+	common := a[len(a)-suffix:]
+	a, b = a[:len(a)-suffix], b[:len(b)-suffix]
+
+	switch {
+	case len(a) == 0:
+		for _, line := range b {
+			*edits = append(*edits, lineEdit{'+', line})
+		}
+	case len(b) == 0:
+		for _, line := range a {
+			*edits = append(*edits, lineEdit{'-', line})
+		}
+	default:
+		x, y, u, v := middleSnake(a, b)
+		diffRange(a[:x], b[:y], edits)
+		for _, line := range a[x:u] {
+			*edits = append(*edits, lineEdit{' ', line})
+		}
+		diffRange(a[u:], b[v:], edits)
+	}
+	for _, line := range common {
+		*edits = append(*edits, lineEdit{' ', line})
+	}
+}
+
+// middleSnake returns the snake from (x, y) to (u, v) in the middle of a
+// shortest edit script turning a into b, searching forwards from the start
+// and backwards from the end until the two searches meet.
+func middleSnake(a, b []string) (x, y, u, v int) {
+	n, m := len(a), len(b)
+	delta := n - m
+	odd := delta%2 != 0
+	limit := (n+m+1)/2 + 1
+	offset := limit
+	// forward[offset+k] is the furthest x reached on diagonal k from the
+	// start, backward[offset+k] the furthest distance from the end on
+	// diagonal k of the reversed files.
+	forward := make([]int, 2*limit+1)
+	backward := make([]int, 2*limit+1)
+
+	for d := 0; d < limit; d++ {
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && forward[offset+k-1] < forward[offset+k+1]) {
+				x = forward[offset+k+1]
+			} else {
+				x = forward[offset+k-1] + 1
+			}
+			y := x - k
+			startX, startY := x, y
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			forward[offset+k] = x
+			if odd && delta-k >= -(d-1) && delta-k <= d-1 && x+backward[offset+delta-k] >= n {
+				return startX, startY, x, y
+			}
+		}
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && backward[offset+k-1] < backward[offset+k+1]) {
+				x = backward[offset+k+1]
+			} else {
+				x = backward[offset+k-1] + 1
+			}
+			y := x - k
+			startX, startY := x, y
+			for x < n && y < m && a[n-1-x] == b[m-1-y] {
+				x++
+				y++
+			}
+			backward[offset+k] = x
+			if !odd && delta-k >= -d && delta-k <= d && x+forward[offset+delta-k] >= n {
+				return n - x, m - y, n - startX, m - startY
+			}
+		}
+	}
+	panic("diff: no middle snake")
+}
@@ -0,0 +1,126 @@
+package generator
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	tests := []struct {
+		name string
+		old  string
+		new  string
+		want string
+	}{
+		{
+			name: "equal",
+			old:  "a\nb\n",
+			new:  "a\nb\n",
+			want: "",
+		},
+		{
+			name: "new file",
+			old:  "",
+			new:  "a\nb\n",
+			want: "--- old\n+++ new\n@@ -0,0 +1,2 @@\n+a\n+b\n",
+		},
+		{
+			name: "changed line",
+			old:  "1\n2\n3\n4\n5\n6\n7\n8\n9\n",
+			new:  "1\n2\n3\n4\nfive\n6\n7\n8\n9\n",
+			want: "--- old\n+++ new\n@@ -2,7 +2,7 @@\n 2\n 3\n 4\n-5\n+five\n 6\n 7\n 8\n",
+		},
+		{
+			name: "separate hunks",
+			old:  "a\n1\n2\n3\n4\n5\n6\n7\n8\nb\n",
+			new:  "A\n1\n2\n3\n4\n5\n6\n7\n8\n",
+			want: "--- old\n+++ new\n@@ -1,4 +1,4 @@\n-a\n+A\n 1\n 2\n 3\n@@ -7,4 +7,3 @@\n 6\n 7\n 8\n-b\n",
+		},
+		{
+			name: "merged hunks",
+			old:  "a\n1\n2\n3\n4\n5\n6\nb\n",
+			new:  "A\n1\n2\n3\n4\n5\n6\nB\n",
+			want: "--- old\n+++ new\n@@ -1,8 +1,8 @@\n-a\n+A\n 1\n 2\n 3\n 4\n 5\n 6\n-b\n+B\n",
+		},
+		{
+			name: "insertion",
+			old:  "a\nb\n",
+			new:  "a\nx\nb\n",
+			want: "--- old\n+++ new\n@@ -1,2 +1,3 @@\n a\n+x\n b\n",
+		},
+		{
+			name: "final newline removed",
+			old:  "a\nb\n",
+			new:  "a\nb",
+			want: "--- old\n+++ new\n@@ -1,2 +1,2 @@\n a\n-b\n+b\n\\ No newline at end of file\n",
+		},
+		{
+			name: "replaced block",
+			old:  "a\nb\nc\n",
+			new:  "x\ny\n",
+			want: "--- old\n+++ new\n@@ -1,3 +1,2 @@\n-a\n-b\n-c\n+x\n+y\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := unifiedDiff("old", "new", []byte(tt.old), []byte(tt.new))
+			if got != tt.want {
+				t.Errorf("unifiedDiff() =\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiffLinesShortest(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	randomLines := func() []string {
+		lines := make([]string, rng.Intn(30))
+		for i := range lines {
+			lines[i] = string(rune('a' + rng.Intn(4)))
+		}
+		return lines
+	}
+
+	for i := 0; i < 500; i++ {
+		a, b := randomLines(), randomLines()
+		var gotA, gotB []string
+		changes := 0
+		for _, e := range diffLines(a, b) {
+			if e.op != '+' {
+				gotA = append(gotA, e.line)
+			}
+			if e.op != '-' {
+				gotB = append(gotB, e.line)
+			}
+			if e.op != ' ' {
+				changes++
+			}
+		}
+		if !slices.Equal(gotA, a) || !slices.Equal(gotB, b) {
+			t.Fatalf("diffLines(%q, %q) does not rebuild both sides", a, b)
+		}
+		if want := len(a) + len(b) - 2*lcsLength(a, b); changes != want {
+			t.Fatalf("diffLines(%q, %q) has %d changes, want %d", a, b, changes, want)
+		}
+	}
+}
+
+// lcsLength returns the length of the longest common subsequence of a and
+// b, which determines the length of a shortest edit script.
+func lcsLength(a, b []string) int {
+	prev := make([]int, len(b)+1)
+	for i := range a {
+		cur := make([]int, len(b)+1)
+		for j := range b {
+			if a[i] == b[j] {
+				cur[j+1] = prev[j] + 1
+			} else {
+				cur[j+1] = max(prev[j+1], cur[j])
+			}
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
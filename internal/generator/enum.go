@@ -0,0 +1,141 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/ttaatoo/sqlgen/internal/schema"
+)
+
+// Enum columns get a named string type, such as UsersStatus for the status
+// column of users, with a constant per allowed value:
+//
+//	func (UsersStatus) Values() []UsersStatus
+//	func (u UsersStatus) IsValid() bool
+//	func (u UsersStatus) String() string
+//	func (u *UsersStatus) Scan(src any) error
+//	func (u UsersStatus) Value() (driver.Value, error)
+//
+// A type override of the column, such as enum: string, turns this off.
+// Names in the generated code other than the receiver have more than one
+// letter, so that they cannot clash with it.
+
+// enumType returns the name of the Go type generated for col of table, or
+// "" if col is not an enum column or its type is overridden.
+func (g *Generator) enumType(table *schema.Table, col schema.Column) string {
+	if len(col.EnumValues) == 0 || col.IsArray {
+		return ""
+	}
+	if _, ok := g.override(table, col); ok {
+		return ""
+	}
+	return g.camel(table.Name) + g.camel(col.Name)
+}
+
+// enumColumns returns the columns of table that get an enum type.
+func (g *Generator) enumColumns(table *schema.Table) []schema.Column {
+	var cols []schema.Column
+	for _, col := range table.Columns {
+		if g.enumType(table, col) != "" {
+			cols = append(cols, col)
+		}
+	}
+	return cols
+}
+
+// enumImports returns the imports used by the enum types of table.
+func (g *Generator) enumImports(table *schema.Table) []string {
+	if len(g.enumColumns(table)) == 0 {
+		return nil
+	}
+	return []string{"database/sql/driver", "fmt"}
+}
+
+// constNames returns the names of the constants for the values of the enum
+// or set type typeName. Each is the type name followed by the value in
+// CamelCase, as in UsersStatusInProgress for "in progress", made unique if
+// needed.
+func (g *Generator) constNames(typeName string, values []string) []string {
+	names := make([]string, len(values))
+	seen := make(map[string]bool, len(values))
+	for i, value := range values {
+		parts := strings.FieldsFunc(value, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		})
+		for j, part := range parts {
+			if part == strings.ToUpper(part) {
+				parts[j] = strings.ToLower(part)
+			}
+		}
+		suffix := g.camel(strings.Join(parts, "_"))
+		if suffix == "" {
+			suffix = "Empty"
+		}
+		name := typeName + suffix
+		for n := 2; seen[name]; n++ {
+			name = typeName + suffix + strconv.Itoa(n)
+		}
+		seen[name] = true
+		names[i] = name
+	}
+	return names
+}
+
+func (g *Generator) generateEnums(buf *bytes.Buffer, table *schema.Table) {
+	for _, col := range g.enumColumns(table) {
+		g.generateEnum(buf, table, col)
+	}
+}
+
+func (g *Generator) generateEnum(buf *bytes.Buffer, table *schema.Table, col schema.Column) {
+	typeName := g.enumType(table, col)
+	recv := receiverName(typeName)
+	names := g.constNames(typeName, col.EnumValues)
+
+	buf.WriteString(fmt.Sprintf("\n// %s is a value of the enum column %s of %s.\n", typeName, col.Name, table.Name))
+	buf.WriteString(fmt.Sprintf("type %s string\n", typeName))
+
+	buf.WriteString(fmt.Sprintf("\n// Values of %s.\nconst (\n", typeName))
+	for i, value := range col.EnumValues {
+		buf.WriteString(fmt.Sprintf("\t%s %s = %s\n", names[i], typeName, strconv.Quote(value)))
+	}
+	buf.WriteString(")\n")
+
+	buf.WriteString(fmt.Sprintf("\n// Values returns the values of %s in declaration order.\n", typeName))
+	buf.WriteString(fmt.Sprintf("func (%s) Values() []%s {\n", typeName, typeName))
+	buf.WriteString(fmt.Sprintf("\treturn []%s{%s}\n", typeName, strings.Join(names, ", ")))
+	buf.WriteString("}\n")
+
+	buf.WriteString(fmt.Sprintf("\n// IsValid reports whether %s is one of the values of %s.\n", recv, typeName))
+	buf.WriteString(fmt.Sprintf("func (%s %s) IsValid() bool {\n", recv, typeName))
+	buf.WriteString(fmt.Sprintf("\tswitch %s {\n\tcase %s:\n\t\treturn true\n\t}\n", recv, strings.Join(names, ", ")))
+	buf.WriteString("\treturn false\n")
+	buf.WriteString("}\n")
+
+	buf.WriteString(fmt.Sprintf("\n// String returns %s as a string.\n", recv))
+	buf.WriteString(fmt.Sprintf("func (%s %s) String() string {\n", recv, typeName))
+	buf.WriteString(fmt.Sprintf("\treturn string(%s)\n", recv))
+	buf.WriteString("}\n")
+
+	buf.WriteString("\n// Scan implements sql.Scanner. Values unknown to this version of the\n")
+	buf.WriteString("// schema are kept, so that IsValid can report them.\n")
+	buf.WriteString(fmt.Sprintf("func (%s *%s) Scan(src any) error {\n", recv, typeName))
+	buf.WriteString("\tswitch value := src.(type) {\n")
+	buf.WriteString(fmt.Sprintf("\tcase string:\n\t\t*%s = %s(value)\n", recv, typeName))
+	buf.WriteString(fmt.Sprintf("\tcase []byte:\n\t\t*%s = %s(value)\n", recv, typeName))
+	buf.WriteString(fmt.Sprintf("\tdefault:\n\t\treturn fmt.Errorf(\"cannot scan %%T into %s\", src)\n", typeName))
+	buf.WriteString("\t}\n")
+	buf.WriteString("\treturn nil\n")
+	buf.WriteString("}\n")
+
+	buf.WriteString(fmt.Sprintf("\n// Value implements driver.Valuer. It refuses values other than those of\n// %s.\n", typeName))
+	buf.WriteString(fmt.Sprintf("func (%s %s) Value() (driver.Value, error) {\n", recv, typeName))
+	buf.WriteString(fmt.Sprintf("\tif !%s.IsValid() {\n", recv))
+	buf.WriteString(fmt.Sprintf("\t\treturn nil, fmt.Errorf(\"invalid %s value %%q\", string(%s))\n", typeName, recv))
+	buf.WriteString("\t}\n")
+	buf.WriteString(fmt.Sprintf("\treturn string(%s), nil\n", recv))
+	buf.WriteString("}\n")
+}
@@ -0,0 +1,148 @@
+package generator
+
+import (
+	"go/format"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ttaatoo/sqlgen/internal/schema"
+)
+
+func TestGenerateEnum(t *testing.T) {
+	gen := New("models", "/tmp/output")
+
+	src, err := format.Source([]byte(mustRender(t, gen, testTable("enum"))))
+	if err != nil {
+		t.Fatalf("generated code does not parse: %v", err)
+	}
+	code := string(src)
+	for _, want := range []string{
+		"\"database/sql/driver\"\n\t\"fmt\"\n",
+		"Status   TasksStatus    `db:\"status\"`",
+		"Priority *TasksPriority `db:\"priority\"`",
+		"// TasksStatus is a value of the enum column status of tasks.\ntype TasksStatus string\n",
+		"TasksStatusTodo       TasksStatus = \"todo\"\n" +
+			"\tTasksStatusInProgress TasksStatus = \"in progress\"\n" +
+			"\tTasksStatusDone       TasksStatus = \"DONE\"\n",
+		"func (TasksStatus) Values() []TasksStatus {\n\treturn []TasksStatus{TasksStatusTodo, TasksStatusInProgress, TasksStatusDone}\n}",
+		"func (t TasksStatus) IsValid() bool {\n\tswitch t {\n\tcase TasksStatusTodo, TasksStatusInProgress, TasksStatusDone:\n\t\treturn true\n\t}",
+		"func (t TasksStatus) String() string {",
+		"func (t *TasksStatus) Scan(src any) error {",
+		"func (t TasksStatus) Value() (driver.Value, error) {",
+		"type TasksPriority string",
+		"TasksPriorityHigh TasksPriority = \"high\"",
+	} {
+		if !strings.Contains(code, want) {
+			t.Errorf("generated code should contain %q\n%s", want, code)
+		}
+	}
+}
+
+func TestGenerateEnumOverridden(t *testing.T) {
+	gen := New("models", "/tmp/output", WithTypeOverrides(map[string]TypeOverride{"enum": {Type: "string"}}))
+
+	code := mustRender(t, gen, testTable("enum"))
+	for _, unwanted := range []string{"TasksStatus", "TasksPriority", "driver"} {
+		if strings.Contains(code, unwanted) {
+			t.Errorf("overridden enum columns should not contain %q\n%s", unwanted, code)
+		}
+	}
+	if !strings.Contains(code, "Status string `db:\"status\"`") {
+		t.Errorf("overridden enum column should be a string\n%s", code)
+	}
+}
+
+func TestGenerateEnumClash(t *testing.T) {
+	statusTable := &schema.Table{Name: "tasks_status", Columns: []schema.Column{{Name: "id", DataType: "int"}}}
+	tests := []struct {
+		name   string
+		tables []*schema.Table
+	}{
+		{"enum first", []*schema.Table{testTable("enum"), statusTable}},
+		{"table first", []*schema.Table{statusTable, testTable("enum")}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gen := New("models", "/tmp/output", WithDryRun(io.Discard))
+			if err := gen.Generate(tt.tables[0]); err != nil {
+				t.Fatalf("Generate() error = %v", err)
+			}
+			// Generating a table again declares the same types.
+			if err := gen.Generate(tt.tables[0]); err != nil {
+				t.Fatalf("Generate() again error = %v", err)
+			}
+			err := gen.Generate(tt.tables[1])
+			if err == nil || !strings.Contains(err.Error(), "type TasksStatus") {
+				t.Errorf("Generate() error = %v, want a clash of TasksStatus", err)
+			}
+		})
+	}
+}
+
+func TestEnumGoType(t *testing.T) {
+	table := testTable("enum")
+	tests := []struct {
+		nullable Nullable
+		want     string
+	}{
+		{NullPointer, "*TasksPriority"},
+		{NullSQL, "sql.Null[TasksPriority]"},
+		{NullGeneric, "sql.Null[TasksPriority]"},
+	}
+
+	for _, tt := range tests {
+		gen := New("models", "/tmp/output", WithNullable(tt.nullable))
+		if got := gen.goType(table, table.Columns[2]); got != tt.want {
+			t.Errorf("goType() with %s = %q, want %q", tt.nullable, got, tt.want)
+		}
+	}
+
+	// Postgres arrays of enums stay string arrays.
+	gen := New("models", "/tmp/output")
+	col := schema.Column{Name: "moods", DataType: "enum", IsArray: true, EnumValues: []string{"ok"}}
+	if got := gen.goType(&schema.Table{Name: "t", Dialect: schema.Postgres}, col); got != "pq.StringArray" {
+		t.Errorf("goType() of enum array = %q, want pq.StringArray", got)
+	}
+}
+
+func TestEnumScanValue(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "sqlgen_test")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	if err := New("models", tmpDir, WithCRUD(true)).Generate(testTable("enum")); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	runGenerated(t, tmpDir, `package models
+
+import "testing"
+
+func TestEnum(t *testing.T) {
+	var s TasksStatus
+	if err := s.Scan([]byte("in progress")); err != nil || s != TasksStatusInProgress {
+		t.Errorf("Scan() = %q, %v, want %q", s, err, TasksStatusInProgress)
+	}
+	if v, err := TasksStatusDone.Value(); v != "DONE" || err != nil {
+		t.Errorf("Value() = %#v, %v, want DONE", v, err)
+	}
+	if _, err := TasksStatus("done").Value(); err == nil {
+		t.Error("Value() of an unknown value should fail")
+	}
+}
+`)
+}
+
+func TestEnumConstNames(t *testing.T) {
+	gen := New("models", "/tmp/output")
+	got := gen.constNames("Kind", []string{"a-b", "a_b", "", "user_id", "2fa", "ÜBER cool"})
+	want := []string{"KindAB", "KindAB2", "KindEmpty", "KindUserID", "Kind2fa", "KindÜberCool"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("constNames() = %q, want %q", got, want)
+	}
+}
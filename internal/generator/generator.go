@@ -2,21 +2,56 @@ package generator
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
+	"go/ast"
 	"go/format"
+	"go/parser"
+	"go/token"
+	"io"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
+	"text/template"
 	"unicode"
+	"unicode/utf8"
 
 	"github.com/ttaatoo/sqlgen/internal/schema"
 )
 
 type Generator struct {
-	packageName  string
-	outputDir    string
-	force        bool
-	confirmFunc  func(filename string) bool
+	packageName string
+	outputDir   string
+	force       bool
+	confirmFunc func(filename string) bool
+	relations   bool
+	crud        bool
+	template    *template.Template
+	types       map[string]TypeOverride
+	patterns    []string
+	decimal     TypeOverride
+	json        TypeOverride
+	nullable    Nullable
+	bools       bool
+	tags        []Tag
+	initialisms map[string]bool
+	source      string
+	version     string
+	checkOut    io.Writer
+	dryRunOut   io.Writer
+
+	// sharedWritten records that the shared helper file has been written.
+	sharedWritten bool
+	// generated holds the names of the files generated this run, which
+	// Prune keeps.
+	generated map[string]bool
+	// kept holds the names of the files Keep marked, which Prune keeps too.
+	kept map[string]bool
+	// declared maps the types generated this run to what they were
+	// generated for, such as "table users".
+	declared map[string]string
 }
 
 type Option func(*Generator)
@@ -33,10 +68,152 @@ func WithConfirmFunc(fn func(filename string) bool) Option {
 	}
 }
 
+// WithRelations enables belongs-to and has-many loader methods derived from
+// foreign keys.
+func WithRelations(enabled bool) Option {
+	return func(g *Generator) {
+		g.relations = enabled
+	}
+}
+
+// WithCRUD enables Insert, Get, Update and Delete functions for each table.
+func WithCRUD(enabled bool) Option {
+	return func(g *Generator) {
+		g.crud = enabled
+	}
+}
+
+// WithTemplate replaces the built-in templates with tmpl, as returned by
+// ParseTemplates.
+func WithTemplate(tmpl *template.Template) Option {
+	return func(g *Generator) {
+		g.template = tmpl
+	}
+}
+
+// WithTypeOverrides replaces the built-in mapping of the columns matching
+// each key, which is a data type such as "json", a full column type such as
+// "char(36)", or a "table.column" path.Match pattern such as "users.id" or
+// "*.uuid". Keys are case-insensitive.
+func WithTypeOverrides(types map[string]TypeOverride) Option {
+	return func(g *Generator) {
+		g.types = make(map[string]TypeOverride, len(types))
+		g.patterns = nil
+		for key, o := range types {
+			key = strings.ToLower(key)
+			g.types[key] = o
+			if strings.Contains(key, ".") {
+				g.patterns = append(g.patterns, key)
+			}
+		}
+		// Longer patterns are tried first, as they tend to be more specific.
+		sort.Slice(g.patterns, func(i, j int) bool {
+			if len(g.patterns[i]) != len(g.patterns[j]) {
+				return len(g.patterns[i]) > len(g.patterns[j])
+			}
+			return g.patterns[i] < g.patterns[j]
+		})
+	}
+}
+
+// WithDecimal sets the Go type of DECIMAL and NUMERIC columns, as returned
+// by ParseDecimal. Overrides from WithTypeOverrides take precedence.
+func WithDecimal(decimal TypeOverride) Option {
+	return func(g *Generator) {
+		g.decimal = decimal
+	}
+}
+
+// WithBool maps the TINYINT(1) columns of MySQL tables to bool and their
+// BIT(1) columns to Bit, a bool type declared in the shared file that scans
+// the bytes the driver returns for BIT. Overrides from WithTypeOverrides
+// take precedence.
+func WithBool(enabled bool) Option {
+	return func(g *Generator) {
+		g.bools = enabled
+	}
+}
+
+// WithJSON sets the Go type of JSON columns, as returned by ParseJSON.
+// Overrides from WithTypeOverrides take precedence.
+func WithJSON(json TypeOverride) Option {
+	return func(g *Generator) {
+		g.json = json
+	}
+}
+
+// WithNullable sets how nullable columns are represented; see Nullable.
+func WithNullable(nullable Nullable) Option {
+	return func(g *Generator) {
+		g.nullable = nullable
+	}
+}
+
+// WithTags adds struct tags next to db:"...", in order; see Tag.
+func WithTags(tags ...Tag) Option {
+	return func(g *Generator) {
+		g.tags = tags
+	}
+}
+
+// WithSource names the schema source in the header of generated files. It
+// should not contain passwords.
+func WithSource(source string) Option {
+	return func(g *Generator) {
+		g.source = source
+	}
+}
+
+// WithVersion sets the sqlgen version named in the header of generated
+// files. The header omits the version if it is "".
+func WithVersion(version string) Option {
+	return func(g *Generator) {
+		g.version = version
+	}
+}
+
+// WithInitialisms adds words, such as "SKU" or "ISBN", to the built-in
+// initialisms written in upper case when they make up a whole part of a
+// snake_case name.
+func WithInitialisms(words ...string) Option {
+	return func(g *Generator) {
+		for _, w := range words {
+			g.initialisms[strings.ToUpper(w)] = true
+		}
+	}
+}
+
+// WithCheck switches the generator to check mode: instead of writing files
+// it compares them with the files on disk, writes a unified diff of each
+// stale file to w and returns ErrStale. A nil w disables check mode.
+func WithCheck(w io.Writer) Option {
+	return func(g *Generator) {
+		g.checkOut = w
+	}
+}
+
+// WithDryRun writes every generated file to w, each preceded by a
+// "// ==> path <==" line, instead of to the output directory. Nothing on
+// disk is touched and no overwrite confirmation is asked for. A nil w
+// disables dry-run mode.
+func WithDryRun(w io.Writer) Option {
+	return func(g *Generator) {
+		g.dryRunOut = w
+	}
+}
+
 func New(packageName, outputDir string, opts ...Option) *Generator {
 	g := &Generator{
 		packageName: packageName,
 		outputDir:   outputDir,
+		template:    template.Must(ParseTemplates("")),
+		initialisms: make(map[string]bool, len(commonInitialisms)),
+		generated:   make(map[string]bool),
+		kept:        make(map[string]bool),
+		declared:    make(map[string]string),
+	}
+	for w := range commonInitialisms {
+		g.initialisms[w] = true
 	}
 	for _, opt := range opts {
 		opt(g)
@@ -44,31 +221,149 @@ func New(packageName, outputDir string, opts ...Option) *Generator {
 	return g
 }
 
+// Checking reports whether the generator is in check mode.
+func (g *Generator) Checking() bool {
+	return g.checkOut != nil
+}
+
 // ErrSkipped is returned when user chooses to skip overwriting a file
 var ErrSkipped = fmt.Errorf("skipped")
 
+// ErrNotGenerated is returned instead of overwriting an existing file that
+// lacks the generated code header, unless forced.
+var ErrNotGenerated = fmt.Errorf("file was not generated by sqlgen")
+
+// ErrStale is returned in check mode when a generated file differs from
+// the file on disk.
+var ErrStale = fmt.Errorf("generated code is out of date")
+
 func (g *Generator) Generate(table *schema.Table) error {
-	if err := os.MkdirAll(g.outputDir, 0755); err != nil {
-		return fmt.Errorf("failed to create output directory: %w", err)
+	filename := toSnakeCase(table.Name) + ".go"
+	if filename == sharedFilename {
+		return fmt.Errorf("table %s cannot be generated into %s, which holds the shared helpers", table.Name, sharedFilename)
+	}
+	if err := g.declare(table); err != nil {
+		return err
+	}
+	if g.checkOut == nil && g.dryRunOut == nil {
+		if err := os.MkdirAll(g.outputDir, 0755); err != nil {
+			return fmt.Errorf("failed to create output directory: %w", err)
+		}
 	}
 
-	code := g.generateStruct(table)
+	// A stale shared file is reported after the table's own file has been
+	// checked too.
+	var sharedErr error
+	if g.needsShared(table) && !g.sharedWritten {
+		g.generated[sharedFilename] = true
+		sharedErr = g.writeFile(sharedFilename, g.header(nil)+g.generateShared(table.Dialect))
+		if sharedErr != nil && !errors.Is(sharedErr, ErrStale) {
+			return sharedErr
+		}
+		g.sharedWritten = true
+	}
+
+	g.generated[filename] = true
+
+	code, err := g.render(table)
+	if err != nil {
+		return err
+	}
+	// The header is added even if a custom template writes its own, which
+	// sqlgen would not recognize as its header later.
+	if err := g.writeFile(filename, g.header(table)+code); err != nil {
+		return err
+	}
+	return sharedErr
+}
+
+// declare records the types generated for table, failing if one of them
+// was already generated for another table or column, as the enum type
+// UsersStatus of users.status would be for a table users_status, or the
+// set type UsersPerms of users.perms for a table users_perms.
+func (g *Generator) declare(table *schema.Table) error {
+	types := [][2]string{{g.camel(table.Name), "table " + table.Name}}
+	for _, col := range g.enumColumns(table) {
+		types = append(types, [2]string{g.enumType(table, col), "enum column " + table.Name + "." + col.Name})
+	}
+	for _, col := range g.setColumns(table) {
+		types = append(types, [2]string{g.setType(table, col), "set column " + table.Name + "." + col.Name})
+	}
+	for _, t := range types {
+		if owner, ok := g.declared[t[0]]; ok && owner != t[1] {
+			return fmt.Errorf("type %s of %s is already generated for %s; override the column's type or leave one of the tables out", t[0], t[1], owner)
+		}
+	}
+	for _, t := range types {
+		g.declared[t[0]] = t[1]
+	}
+	return nil
+}
+
+// header returns the comment marking a file as generated from table of the
+// generator's source, or from the source as a whole if table is nil.
+func (g *Generator) header(table *schema.Table) string {
+	var buf strings.Builder
+	if g.version != "" {
+		fmt.Fprintf(&buf, "// Code generated by sqlgen %s. DO NOT EDIT.\n", g.version)
+	} else {
+		buf.WriteString("// Code generated by sqlgen. DO NOT EDIT.\n")
+	}
+	if g.source != "" {
+		fmt.Fprintf(&buf, "// Source: %s\n", g.source)
+	}
+	switch {
+	case table == nil:
+	case table.IsView():
+		fmt.Fprintf(&buf, "// View: %s\n", table.Name)
+	default:
+		fmt.Fprintf(&buf, "// Table: %s\n", table.Name)
+	}
+	return buf.String() + "\n"
+}
+
+// isGenerated reports whether the Go source in content was generated by
+// sqlgen: it starts with sqlgen's header and is marked as generated before
+// its package clause. Files of other generators, such as stringer, are not.
+func isGenerated(content []byte) bool {
+	if !bytes.HasPrefix(content, []byte(sqlgenMarker)) {
+		return false
+	}
+	file, err := parser.ParseFile(token.NewFileSet(), "", content, parser.PackageClauseOnly|parser.ParseComments)
+	return err == nil && ast.IsGenerated(file)
+}
+
+// writeFile formats code and writes it to filename in the output directory.
+// An existing file is only replaced if it was generated, and then after
+// asking, unless forced.
+func (g *Generator) writeFile(filename, code string) error {
 	formatted, err := format.Source([]byte(code))
 	if err != nil {
 		return fmt.Errorf("failed to format code: %w\ngenerated code:\n%s", err, code)
 	}
 
-	filename := toSnakeCase(table.Name) + ".go"
 	filePath := filepath.Join(g.outputDir, filename)
 
-	// Check if file exists
-	if _, err := os.Stat(filePath); err == nil {
-		// File exists
-		if !g.force && g.confirmFunc != nil {
-			if !g.confirmFunc(filePath) {
-				return ErrSkipped
-			}
+	if g.checkOut != nil {
+		return g.checkFile(filePath, formatted)
+	}
+	if g.dryRunOut != nil {
+		if _, err := fmt.Fprintf(g.dryRunOut, "// ==> %s <==\n%s", filePath, formatted); err != nil {
+			return fmt.Errorf("failed to write file: %w", err)
 		}
+		return nil
+	}
+
+	existing, err := os.ReadFile(filePath)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+	case err != nil:
+		return fmt.Errorf("failed to read file: %w", err)
+	case g.force:
+	case !isGenerated(existing):
+		return fmt.Errorf("%s: %w", filePath, ErrNotGenerated)
+	case g.confirmFunc != nil && !g.confirmFunc(filePath):
+		return ErrSkipped
 	}
 
 	if err := os.WriteFile(filePath, formatted, 0644); err != nil {
@@ -78,43 +373,47 @@ func (g *Generator) Generate(table *schema.Table) error {
 	return nil
 }
 
-func (g *Generator) generateStruct(table *schema.Table) string {
-	var buf bytes.Buffer
-
-	buf.WriteString(fmt.Sprintf("package %s\n\n", g.packageName))
-
-	imports := g.collectImports(table)
-	if len(imports) > 0 {
-		buf.WriteString("import (\n")
-		for _, imp := range imports {
-			buf.WriteString(fmt.Sprintf("\t%q\n", imp))
-		}
-		buf.WriteString(")\n\n")
+// checkFile compares the generated code for filePath with the file on disk,
+// a missing file counting as empty.
+func (g *Generator) checkFile(filePath string, code []byte) error {
+	oldName := filePath
+	existing, err := os.ReadFile(filePath)
+	if errors.Is(err, fs.ErrNotExist) {
+		oldName = "/dev/null"
+	} else if err != nil {
+		return fmt.Errorf("failed to read file: %w", err)
+	} else if bytes.Equal(existing, code) {
+		return nil
 	}
 
-	structName := toCamelCase(table.Name)
-	buf.WriteString(fmt.Sprintf("type %s struct {\n", structName))
-
-	for _, col := range table.Columns {
-		fieldName := toCamelCase(col.Name)
-		fieldType := mysqlTypeToGo(col.DataType, col.IsNullable, col.IsUnsigned)
-		tag := fmt.Sprintf("`db:\"%s\"`", col.Name)
-
-		buf.WriteString(fmt.Sprintf("\t%s %s %s\n", fieldName, fieldType, tag))
+	diff := unifiedDiff(oldName, filePath, existing, code)
+	if _, err := io.WriteString(g.checkOut, diff); err != nil {
+		return fmt.Errorf("failed to write diff: %w", err)
 	}
-
-	buf.WriteString("}\n")
-
-	return buf.String()
+	return ErrStale
 }
 
 func (g *Generator) collectImports(table *schema.Table) []string {
 	imports := make(map[string]bool)
 
 	for _, col := range table.Columns {
-		switch col.DataType {
-		case "datetime", "timestamp", "date", "time":
-			imports["time"] = true
+		for _, path := range g.typeImports(table, col) {
+			imports[path] = true
+		}
+	}
+
+	for _, imp := range g.enumImports(table) {
+		imports[imp] = true
+	}
+	for _, imp := range g.setImports(table) {
+		imports[imp] = true
+	}
+	if g.hasCRUD(table) {
+		imports["context"] = true
+	}
+	if g.relations {
+		for _, imp := range relationImports(table) {
+			imports[imp] = true
 		}
 	}
 
@@ -122,79 +421,52 @@ func (g *Generator) collectImports(table *schema.Table) []string {
 	for imp := range imports {
 		result = append(result, imp)
 	}
+	sort.Strings(result)
 	return result
 }
 
-func mysqlTypeToGo(dataType string, nullable bool, unsigned bool) string {
-	var goType string
-
-	switch dataType {
-	case "tinyint":
-		if unsigned {
-			goType = "uint8"
-		} else {
-			goType = "int8"
-		}
-	case "smallint":
-		if unsigned {
-			goType = "uint16"
-		} else {
-			goType = "int16"
-		}
-	case "mediumint", "int", "integer":
-		if unsigned {
-			goType = "uint32"
-		} else {
-			goType = "int32"
-		}
-	case "bigint":
-		if unsigned {
-			goType = "uint64"
-		} else {
-			goType = "int64"
+// docComment returns text as // comment lines ending in a newline, or ""
+// if text is blank. Line comments cannot be ended early by */ in the text,
+// and control characters, which Go source cannot hold, are dropped.
+func docComment(text string) string {
+	text = strings.Map(func(r rune) rune {
+		if r != '\n' && r != '\t' && unicode.IsControl(r) {
+			return -1
 		}
-	case "float":
-		goType = "float32"
-	case "double", "real":
-		goType = "float64"
-	case "decimal", "numeric":
-		goType = "float64"
-	case "char", "varchar", "text", "tinytext", "mediumtext", "longtext", "enum", "set":
-		goType = "string"
-	case "binary", "varbinary", "blob", "tinyblob", "mediumblob", "longblob":
-		goType = "[]byte"
-	case "datetime", "timestamp", "date", "time":
-		goType = "time.Time"
-	case "year":
-		if unsigned {
-			goType = "uint16"
+		return r
+	}, text)
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return ""
+	}
+	var buf strings.Builder
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimRightFunc(line, unicode.IsSpace)
+		if line == "" {
+			buf.WriteString("//\n")
 		} else {
-			goType = "int16"
+			buf.WriteString("// " + line + "\n")
 		}
-	case "bit":
-		goType = "[]byte"
-	case "json":
-		goType = "string"
-	default:
-		goType = "string"
-	}
-
-	if nullable {
-		switch goType {
-		case "int8", "int16", "int32", "int64", "uint8", "uint16", "uint32", "uint64":
-			return "*" + goType
-		case "float32", "float64":
-			return "*" + goType
-		case "string":
-			return "*string"
-		case "[]byte":
-			return "[]byte"
-		case "time.Time":
-			return "*time.Time"
+	}
+	return buf.String()
+}
+
+// indent returns text with a tab before each non-empty line, which makes
+// it a code block in a doc comment.
+func indent(text string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		if strings.TrimSpace(line) != "" {
+			lines[i] = "\t" + line
 		}
 	}
+	return strings.Join(lines, "\n")
+}
 
-	return goType
+// camel converts a snake_case name to CamelCase, upper-casing the parts
+// that are initialisms.
+func (g *Generator) camel(s string) string {
+	return camelCase(s, g.initialisms)
 }
 
 func toSnakeCase(s string) string {
@@ -212,11 +484,34 @@ func toSnakeCase(s string) string {
 	return result.String()
 }
 
-func toCamelCase(s string) string {
+// commonInitialisms are the initialisms Go style writes in upper case, as
+// listed by golint.
+var commonInitialisms = map[string]bool{
+	"ACL": true, "API": true, "ASCII": true, "CPU": true, "CSS": true,
+	"DNS": true, "EOF": true, "GUID": true, "HTML": true, "HTTP": true,
+	"HTTPS": true, "ID": true, "IP": true, "JSON": true, "LHS": true,
+	"QPS": true, "RAM": true, "RHS": true, "RPC": true, "SLA": true,
+	"SMTP": true, "SQL": true, "SSH": true, "TCP": true, "TLS": true,
+	"TTL": true, "UDP": true, "UI": true, "UID": true, "UUID": true,
+	"URI": true, "URL": true, "UTF8": true, "VM": true, "XML": true,
+	"XMPP": true, "XSRF": true, "XSS": true,
+}
+
+// camelCase converts a snake_case name to CamelCase. Parts that are
+// initialisms are written in upper case, and so are their plurals apart
+// from the final s, as in user_ids to UserIDs.
+func camelCase(s string, initialisms map[string]bool) string {
 	parts := strings.Split(s, "_")
-	for i := range parts {
-		if len(parts[i]) > 0 {
-			parts[i] = strings.ToUpper(parts[i][:1]) + parts[i][1:]
+	for i, part := range parts {
+		upper := strings.ToUpper(part)
+		switch {
+		case initialisms[upper]:
+			parts[i] = upper
+		case len(part) > 2 && strings.HasSuffix(part, "s") && initialisms[upper[:len(upper)-1]]:
+			parts[i] = upper[:len(upper)-1] + "s"
+		case len(part) > 0:
+			r, size := utf8.DecodeRuneInString(part)
+			parts[i] = string(unicode.ToUpper(r)) + part[size:]
 		}
 	}
 	return strings.Join(parts, "")
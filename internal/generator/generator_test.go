@@ -1,112 +1,33 @@
 package generator
 
 import (
+	"bytes"
+	"errors"
+	"go/format"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"strings"
 	"testing"
+	"text/template"
 
 	"github.com/ttaatoo/sqlgen/internal/schema"
 )
 
-func TestMysqlTypeToGo(t *testing.T) {
-	tests := []struct {
-		name     string
-		dataType string
-		nullable bool
-		unsigned bool
-		want     string
-	}{
-		// Integer types (signed)
-		{"tinyint", "tinyint", false, false, "int8"},
-		{"tinyint nullable", "tinyint", true, false, "*int8"},
-		{"smallint", "smallint", false, false, "int16"},
-		{"smallint nullable", "smallint", true, false, "*int16"},
-		{"mediumint", "mediumint", false, false, "int32"},
-		{"int", "int", false, false, "int32"},
-		{"integer", "integer", false, false, "int32"},
-		{"int nullable", "int", true, false, "*int32"},
-		{"bigint", "bigint", false, false, "int64"},
-		{"bigint nullable", "bigint", true, false, "*int64"},
-
-		// Integer types (unsigned)
-		{"tinyint unsigned", "tinyint", false, true, "uint8"},
-		{"tinyint unsigned nullable", "tinyint", true, true, "*uint8"},
-		{"smallint unsigned", "smallint", false, true, "uint16"},
-		{"smallint unsigned nullable", "smallint", true, true, "*uint16"},
-		{"mediumint unsigned", "mediumint", false, true, "uint32"},
-		{"int unsigned", "int", false, true, "uint32"},
-		{"int unsigned nullable", "int", true, true, "*uint32"},
-		{"bigint unsigned", "bigint", false, true, "uint64"},
-		{"bigint unsigned nullable", "bigint", true, true, "*uint64"},
-
-		// Float types
-		{"float", "float", false, false, "float32"},
-		{"float nullable", "float", true, false, "*float32"},
-		{"double", "double", false, false, "float64"},
-		{"double nullable", "double", true, false, "*float64"},
-		{"real", "real", false, false, "float64"},
-		{"decimal", "decimal", false, false, "float64"},
-		{"numeric", "numeric", false, false, "float64"},
-
-		// String types
-		{"char", "char", false, false, "string"},
-		{"char nullable", "char", true, false, "*string"},
-		{"varchar", "varchar", false, false, "string"},
-		{"varchar nullable", "varchar", true, false, "*string"},
-		{"text", "text", false, false, "string"},
-		{"tinytext", "tinytext", false, false, "string"},
-		{"mediumtext", "mediumtext", false, false, "string"},
-		{"longtext", "longtext", false, false, "string"},
-		{"enum", "enum", false, false, "string"},
-		{"set", "set", false, false, "string"},
-		{"json", "json", false, false, "string"},
-
-		// Binary types
-		{"binary", "binary", false, false, "[]byte"},
-		{"binary nullable", "binary", true, false, "[]byte"},
-		{"varbinary", "varbinary", false, false, "[]byte"},
-		{"blob", "blob", false, false, "[]byte"},
-		{"tinyblob", "tinyblob", false, false, "[]byte"},
-		{"mediumblob", "mediumblob", false, false, "[]byte"},
-		{"longblob", "longblob", false, false, "[]byte"},
-		{"bit", "bit", false, false, "[]byte"},
-
-		// Time types
-		{"datetime", "datetime", false, false, "time.Time"},
-		{"datetime nullable", "datetime", true, false, "*time.Time"},
-		{"timestamp", "timestamp", false, false, "time.Time"},
-		{"timestamp nullable", "timestamp", true, false, "*time.Time"},
-		{"date", "date", false, false, "time.Time"},
-		{"time", "time", false, false, "time.Time"},
-		{"year", "year", false, false, "int16"},
-		{"year unsigned", "year", false, true, "uint16"},
-
-		// Unknown type defaults to string
-		{"unknown", "unknown_type", false, false, "string"},
-		{"unknown nullable", "unknown_type", true, false, "*string"},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			got := mysqlTypeToGo(tt.dataType, tt.nullable, tt.unsigned)
-			if got != tt.want {
-				t.Errorf("mysqlTypeToGo(%q, %v, %v) = %q, want %q", tt.dataType, tt.nullable, tt.unsigned, got, tt.want)
-			}
-		})
-	}
-}
-
-func TestToCamelCase(t *testing.T) {
+func TestCamelCase(t *testing.T) {
 	tests := []struct {
 		input string
 		want  string
 	}{
 		{"user", "User"},
-		{"user_id", "UserId"},
+		{"user_id", "UserID"},
+		{"avatar_url", "AvatarURL"},
+		{"user_ids", "UserIDs"},
+		{"api_http_json", "APIHTTPJSON"},
+		{"identity", "Identity"},
 		{"user_account", "UserAccount"},
 		{"created_at", "CreatedAt"},
-		{"id", "Id"},
+		{"id", "ID"},
 		{"USER", "USER"},
 		{"user_name_test", "UserNameTest"},
 		{"", ""},
@@ -116,9 +37,9 @@ func TestToCamelCase(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.input, func(t *testing.T) {
-			got := toCamelCase(tt.input)
+			got := camelCase(tt.input, commonInitialisms)
 			if got != tt.want {
-				t.Errorf("toCamelCase(%q) = %q, want %q", tt.input, got, tt.want)
+				t.Errorf("camelCase(%q) = %q, want %q", tt.input, got, tt.want)
 			}
 		})
 	}
@@ -214,7 +135,7 @@ func TestGenerateStruct(t *testing.T) {
 		},
 	}
 
-	code := gen.generateStruct(table)
+	code := mustRender(t, gen, table)
 
 	// Check package declaration
 	if !strings.Contains(code, "package models") {
@@ -227,8 +148,8 @@ func TestGenerateStruct(t *testing.T) {
 	}
 
 	// Check field names (PascalCase)
-	if !strings.Contains(code, "Id int64") {
-		t.Error("generated code should contain Id field")
+	if !strings.Contains(code, "ID int64") {
+		t.Error("generated code should contain ID field")
 	}
 	if !strings.Contains(code, "Username string") {
 		t.Error("generated code should contain Username field")
@@ -293,6 +214,18 @@ func TestCollectImports(t *testing.T) {
 			},
 			wantImports: []string{"time"},
 		},
+		{
+			name: "postgres json and arrays",
+			table: &schema.Table{
+				Dialect: schema.Postgres,
+				Columns: []schema.Column{
+					{DataType: "jsonb", IsNullable: false},
+					{DataType: "text", IsArray: true},
+					{DataType: "timestamptz", IsNullable: true},
+				},
+			},
+			wantImports: []string{"encoding/json", "github.com/lib/pq", "time"},
+		},
 	}
 
 	for _, tt := range tests {
@@ -395,6 +328,129 @@ func TestGenerateCreatesDirectory(t *testing.T) {
 	}
 }
 
+// generatedFile is the content of a file written by an earlier run.
+const generatedFile = "// Code generated by sqlgen. DO NOT EDIT.\n\npackage models\n"
+
+func TestGenerateRefusesHandWrittenFile(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "sqlgen_test")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	// A marker after the package clause does not count, and neither do the
+	// headers of other generators.
+	for _, content := range []string{
+		"existing content",
+		"package models\n\n// Code generated by sqlgen. DO NOT EDIT.\n",
+		"// Code generated by \"stringer -type=Status\"; DO NOT EDIT.\n\npackage models\n",
+		"// Code generated by MockGen. DO NOT EDIT.\n// Source: users.go\n\npackage models\n",
+	} {
+		existingFile := filepath.Join(tmpDir, "users.go")
+		if err := os.WriteFile(existingFile, []byte(content), 0644); err != nil {
+			t.Fatalf("failed to create existing file: %v", err)
+		}
+
+		confirmCalled := false
+		gen := New("models", tmpDir,
+			WithConfirmFunc(func(filename string) bool {
+				confirmCalled = true
+				return true
+			}),
+		)
+		table := &schema.Table{
+			Name:    "users",
+			Columns: []schema.Column{{Name: "id", DataType: "int"}},
+		}
+
+		if err := gen.Generate(table); !errors.Is(err, ErrNotGenerated) {
+			t.Errorf("Generate() error = %v, want ErrNotGenerated", err)
+		}
+		if confirmCalled {
+			t.Error("confirmFunc should not be called for a hand-written file")
+		}
+		if got, _ := os.ReadFile(existingFile); string(got) != content {
+			t.Error("file should not have been modified")
+		}
+	}
+}
+
+func TestGenerateHeader(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "sqlgen_test")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	gen := New("models", tmpDir, WithCRUD(true), WithSource("mysql://root@tcp(localhost:3306)/shop"), WithVersion("v1.2.0"))
+	table := &schema.Table{
+		Name:    "users",
+		Columns: []schema.Column{{Name: "id", DataType: "int", ColumnKey: "PRI"}},
+	}
+	if err := gen.Generate(table); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	tests := []struct {
+		file string
+		want string
+	}{
+		{"users.go", "// Code generated by sqlgen v1.2.0. DO NOT EDIT.\n// Source: mysql://root@tcp(localhost:3306)/shop\n// Table: users\n\npackage models\n"},
+		{sharedFilename, "// Code generated by sqlgen v1.2.0. DO NOT EDIT.\n// Source: mysql://root@tcp(localhost:3306)/shop\n\npackage models\n"},
+	}
+	for _, tt := range tests {
+		content, err := os.ReadFile(filepath.Join(tmpDir, tt.file))
+		if err != nil {
+			t.Fatalf("failed to read %s: %v", tt.file, err)
+		}
+		if !strings.HasPrefix(string(content), tt.want) {
+			t.Errorf("%s starts with %q, want %q", tt.file, content[:min(len(content), len(tt.want))], tt.want)
+		}
+		if !isGenerated(content) {
+			t.Errorf("%s should be recognized as generated", tt.file)
+		}
+	}
+
+	// Without a version or source the header leaves them out.
+	gen = New("models", tmpDir, WithConfirmFunc(func(string) bool { return true }))
+	if err := gen.Generate(table); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	content, _ := os.ReadFile(filepath.Join(tmpDir, "users.go"))
+	if want := "// Code generated by sqlgen. DO NOT EDIT.\n// Table: users\n\npackage models\n"; !strings.HasPrefix(string(content), want) {
+		t.Errorf("users.go starts with %q, want %q", content, want)
+	}
+}
+
+func TestGenerateCustomTemplateHeader(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "sqlgen_test")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	tmpl, err := template.New("model").Parse("// Code generated by mytool. DO NOT EDIT.\n\npackage {{.Package}}\n")
+	if err != nil {
+		t.Fatalf("failed to parse template: %v", err)
+	}
+	table := &schema.Table{Name: "users", Columns: []schema.Column{{Name: "id", DataType: "int"}}}
+
+	// The second run must recognize the file written by the first.
+	for run := 1; run <= 2; run++ {
+		gen := New("models", tmpDir, WithTemplate(tmpl), WithConfirmFunc(func(string) bool { return true }))
+		if err := gen.Generate(table); err != nil {
+			t.Fatalf("Generate() run %d error = %v", run, err)
+		}
+	}
+	content, err := os.ReadFile(filepath.Join(tmpDir, "users.go"))
+	if err != nil {
+		t.Fatalf("failed to read users.go: %v", err)
+	}
+	if want := "// Code generated by sqlgen. DO NOT EDIT.\n// Table: users\n\n// Code generated by mytool."; !strings.HasPrefix(string(content), want) {
+		t.Errorf("users.go starts with %q, want %q", content, want)
+	}
+}
+
 func TestGenerateSkipsWhenConfirmReturnsFalse(t *testing.T) {
 	tmpDir, err := os.MkdirTemp("", "sqlgen_test")
 	if err != nil {
@@ -402,9 +458,9 @@ func TestGenerateSkipsWhenConfirmReturnsFalse(t *testing.T) {
 	}
 	defer os.RemoveAll(tmpDir)
 
-	// Create existing file
+	// Create existing generated file
 	existingFile := filepath.Join(tmpDir, "users.go")
-	if err := os.WriteFile(existingFile, []byte("existing content"), 0644); err != nil {
+	if err := os.WriteFile(existingFile, []byte(generatedFile), 0644); err != nil {
 		t.Fatalf("failed to create existing file: %v", err)
 	}
 
@@ -428,7 +484,7 @@ func TestGenerateSkipsWhenConfirmReturnsFalse(t *testing.T) {
 
 	// Verify file was not modified
 	content, _ := os.ReadFile(existingFile)
-	if string(content) != "existing content" {
+	if string(content) != generatedFile {
 		t.Error("file should not have been modified")
 	}
 }
@@ -477,9 +533,9 @@ func TestGenerateOverwritesWhenConfirmReturnsTrue(t *testing.T) {
 	}
 	defer os.RemoveAll(tmpDir)
 
-	// Create existing file
+	// Create existing generated file
 	existingFile := filepath.Join(tmpDir, "users.go")
-	if err := os.WriteFile(existingFile, []byte("existing content"), 0644); err != nil {
+	if err := os.WriteFile(existingFile, []byte(generatedFile), 0644); err != nil {
 		t.Fatalf("failed to create existing file: %v", err)
 	}
 
@@ -522,3 +578,478 @@ func TestErrSkipped(t *testing.T) {
 		t.Errorf("ErrSkipped.Error() = %q, want %q", ErrSkipped.Error(), "skipped")
 	}
 }
+
+func mustRender(t *testing.T, gen *Generator, table *schema.Table) string {
+	t.Helper()
+	code, err := gen.render(table)
+	if err != nil {
+		t.Fatalf("render() error = %v", err)
+	}
+	return code
+}
+
+// testTable returns a new copy of the test table of the given kind, which
+// tests may change:
+//
+//	crud      users with an auto-increment key and a generated column
+//	users     users, referenced by orders
+//	orders    orders, referencing users and, as parent, orders
+//	template  user_accounts with a time column, for custom templates
+//	tags      users with sized, unique and nullable columns, for struct tags
+//	view      the view active_users with a comment and definition
+//	enum      tasks with a required and a nullable enum column
+//	set       members with a required and a nullable set column and an enum
+//	bool      flags with TINYINT(1), BIT(1) and wider columns
+//	json      users with required and nullable JSON columns
+func testTable(kind string) *schema.Table {
+	byUser := schema.ForeignKey{Name: "fk_user", Table: "orders", Columns: []string{"user_id"}, RefTable: "users", RefColumns: []string{"id"}}
+	byParent := schema.ForeignKey{Name: "fk_parent", Table: "orders", Columns: []string{"parent_id"}, RefTable: "orders", RefColumns: []string{"id"}}
+	composite := schema.ForeignKey{Name: "fk_pair", Table: "orders", Columns: []string{"user_id", "parent_id"}, RefTable: "pairs", RefColumns: []string{"a", "b"}}
+
+	switch kind {
+	case "crud":
+		return &schema.Table{
+			Name: "users",
+			Columns: []schema.Column{
+				{Name: "id", DataType: "bigint", IsUnsigned: true, ColumnKey: "PRI", Extra: "auto_increment"},
+				{Name: "name", DataType: "varchar"},
+				{Name: "type", DataType: "int", IsNullable: true},
+				{Name: "full_name", DataType: "varchar", IsNullable: true, Extra: "VIRTUAL GENERATED"},
+			},
+		}
+	case "users":
+		return &schema.Table{
+			Name: "users",
+			Columns: []schema.Column{
+				{Name: "id", DataType: "bigint", ColumnKey: "PRI"},
+				{Name: "name", DataType: "varchar"},
+			},
+			ReferencedBy: []schema.ForeignKey{byUser},
+		}
+	case "orders":
+		return &schema.Table{
+			Name: "orders",
+			Columns: []schema.Column{
+				{Name: "id", DataType: "bigint", ColumnKey: "PRI"},
+				{Name: "user_id", DataType: "bigint", ColumnKey: "MUL"},
+				{Name: "parent_id", DataType: "bigint", IsNullable: true},
+			},
+			ForeignKeys:  []schema.ForeignKey{byUser, byParent, composite},
+			ReferencedBy: []schema.ForeignKey{byParent},
+		}
+	case "template":
+		return &schema.Table{
+			Name: "user_accounts",
+			Columns: []schema.Column{
+				{Name: "id", DataType: "bigint", ColumnKey: "PRI"},
+				{Name: "created_at", DataType: "datetime"},
+			},
+		}
+	case "tags":
+		return &schema.Table{
+			Name: "users",
+			Columns: []schema.Column{
+				{Name: "id", DataType: "bigint", ColumnType: "bigint unsigned", IsUnsigned: true, ColumnKey: "PRI", Extra: "auto_increment"},
+				{Name: "email", DataType: "varchar", ColumnType: "varchar(255)", ColumnKey: "UNI"},
+				{Name: "display_name", DataType: "varchar", ColumnType: "varchar(100)", IsNullable: true},
+				{Name: "age", DataType: "int", ColumnType: "int"},
+				{Name: "bio", DataType: "text", ColumnType: "text", IsNullable: true},
+				{Name: "created_at", DataType: "datetime", ColumnType: "datetime"},
+			},
+		}
+	case "view":
+		return &schema.Table{
+			Name:           "active_users",
+			Type:           schema.View,
+			Comment:        "Users seen this month.",
+			ViewDefinition: "SELECT id, email\nFROM users\n\nWHERE active",
+			Columns: []schema.Column{
+				{Name: "id", DataType: "bigint"},
+				{Name: "email", DataType: "varchar"},
+			},
+		}
+	case "enum":
+		return &schema.Table{
+			Name: "tasks",
+			Columns: []schema.Column{
+				{Name: "id", DataType: "int", ColumnKey: "PRI"},
+				{Name: "status", DataType: "enum", EnumValues: []string{"todo", "in progress", "DONE"}},
+				{Name: "priority", DataType: "enum", IsNullable: true, EnumValues: []string{"low", "high"}},
+			},
+		}
+	case "set":
+		return &schema.Table{
+			Name: "members",
+			Columns: []schema.Column{
+				{Name: "id", DataType: "int", ColumnKey: "PRI"},
+				{Name: "perms", DataType: "set", SetMembers: []string{"read", "write", "Admin"}},
+				{Name: "tags", DataType: "set", IsNullable: true, SetMembers: []string{"new"}},
+				{Name: "status", DataType: "enum", EnumValues: []string{"active", "banned"}},
+			},
+		}
+	case "bool":
+		return &schema.Table{
+			Name: "flags",
+			Columns: []schema.Column{
+				{Name: "id", DataType: "int", ColumnType: "int", ColumnKey: "PRI"},
+				{Name: "active", DataType: "tinyint", ColumnType: "tinyint(1)"},
+				{Name: "verified", DataType: "tinyint", ColumnType: "tinyint(1) unsigned", IsUnsigned: true, IsNullable: true},
+				{Name: "hidden", DataType: "bit", ColumnType: "bit(1)"},
+				{Name: "archived", DataType: "bit", ColumnType: "bit(1)", IsNullable: true},
+				{Name: "level", DataType: "tinyint", ColumnType: "tinyint(4)"},
+				{Name: "mask", DataType: "bit", ColumnType: "bit(8)"},
+			},
+		}
+	case "json":
+		return &schema.Table{
+			Name: "users",
+			Columns: []schema.Column{
+				{Name: "id", DataType: "int", ColumnKey: "PRI"},
+				{Name: "settings", DataType: "json"},
+				{Name: "tags", DataType: "json", IsNullable: true},
+				{Name: "extra", DataType: "json", IsNullable: true},
+				{Name: "notes", DataType: "text"},
+			},
+		}
+	}
+	panic("unknown test table " + kind)
+}
+
+// sharedFile generates a table whose columns need no shared type and then
+// table with opts, and returns the shared file that only the latter wrote.
+func sharedFile(t *testing.T, table *schema.Table, opts ...Option) string {
+	t.Helper()
+	tmpDir, err := os.MkdirTemp("", "sqlgen_test")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	gen := New("models", tmpDir, opts...)
+	plain := &schema.Table{Name: "plain", Columns: []schema.Column{{Name: "id", DataType: "int", ColumnType: "int"}}}
+	if err := gen.Generate(plain); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, sharedFilename)); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("shared file should not be written for plain columns: %v", err)
+	}
+
+	if err := gen.Generate(table); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	content, err := os.ReadFile(filepath.Join(tmpDir, sharedFilename))
+	if err != nil {
+		t.Fatalf("failed to read shared file: %v", err)
+	}
+	return string(content)
+}
+
+// writeFiles writes files, mapping names to contents, into dir.
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+}
+
+func TestGenerateWithTagsAndInitialisms(t *testing.T) {
+	gen := New("models", "/tmp/output", WithTags(DefaultTag("json"), DefaultTag("yaml")), WithInitialisms("sku"))
+	table := &schema.Table{
+		Name: "user_url_links",
+		Columns: []schema.Column{
+			{Name: "id", DataType: "bigint"},
+			{Name: "avatar_url", DataType: "varchar"},
+			{Name: "identity", DataType: "varchar"},
+			{Name: "sku", DataType: "varchar"},
+		},
+	}
+
+	code := mustRender(t, gen, table)
+	for _, want := range []string{
+		"type UserURLLinks struct",
+		"\tID int64 `db:\"id\" json:\"id\" yaml:\"id\"`",
+		"\tAvatarURL string `db:\"avatar_url\" json:\"avatar_url\" yaml:\"avatar_url\"`",
+		"\tIdentity string `db:\"identity\" json:\"identity\" yaml:\"identity\"`",
+		"\tSKU string `db:\"sku\" json:\"sku\" yaml:\"sku\"`",
+	} {
+		if !strings.Contains(code, want) {
+			t.Errorf("generated code should contain %q\n%s", want, code)
+		}
+	}
+}
+
+func TestGenerateComments(t *testing.T) {
+	gen := New("models", "/tmp/output")
+	table := &schema.Table{
+		Name:    "users",
+		Comment: "Registered users.\r\nOne row per account.",
+		Columns: []schema.Column{
+			{Name: "id", DataType: "bigint", Comment: "Primary key"},
+			{Name: "note", DataType: "text", Comment: "Free text; may contain */ or //"},
+			{Name: "name", DataType: "varchar"},
+		},
+	}
+
+	src, err := format.Source([]byte(mustRender(t, gen, table)))
+	if err != nil {
+		t.Fatalf("generated code does not parse: %v", err)
+	}
+	code := string(src)
+	for _, want := range []string{
+		"// Users Registered users.\n// One row per account.\ntype Users struct {",
+		"\t// Primary key\n\tID int64 `db:\"id\"`",
+		"\t// Free text; may contain */ or //\n\tNote string `db:\"note\"`\n\tName string `db:\"name\"`",
+	} {
+		if !strings.Contains(code, want) {
+			t.Errorf("generated code should contain %q\n%s", want, code)
+		}
+	}
+}
+
+func TestGenerateView(t *testing.T) {
+	gen := New("models", "/tmp/output")
+	table := testTable("view")
+
+	src, err := format.Source([]byte(gen.header(table) + mustRender(t, gen, table)))
+	if err != nil {
+		t.Fatalf("generated code does not parse: %v", err)
+	}
+	code := string(src)
+	want := "// ActiveUsers Users seen this month.\n" +
+		"//\n" +
+		"// ActiveUsers is a row of the view active_users and is read-only.\n" +
+		"//\n" +
+		"// The view is defined as:\n" +
+		"//\n" +
+		"//\tSELECT id, email\n" +
+		"//\tFROM users\n" +
+		"//\n" +
+		"//\tWHERE active\n" +
+		"type ActiveUsers struct {"
+	if !strings.Contains(code, want) {
+		t.Errorf("generated code should contain %q\n%s", want, code)
+	}
+	if !strings.Contains(code, "// View: active_users\n") {
+		t.Errorf("header should name the view\n%s", code)
+	}
+}
+
+func TestDocComment(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"", ""},
+		{"  \n ", ""},
+		{"Primary key", "// Primary key\n"},
+		{"first\r\n\nthird  ", "// first\n//\n// third\n"},
+		{"ends */ early", "// ends */ early\n"},
+		{"bell\a\x00", "// bell\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			if got := docComment(tt.text); got != tt.want {
+				t.Errorf("docComment(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerateCheck(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "sqlgen_test")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	table := &schema.Table{
+		Name: "users",
+		Columns: []schema.Column{
+			{Name: "id", DataType: "int", IsNullable: false},
+			{Name: "name", DataType: "varchar", IsNullable: false},
+		},
+	}
+
+	// Nothing generated yet: the whole file is reported, nothing is written.
+	outDir := filepath.Join(tmpDir, "models")
+	var diff strings.Builder
+	gen := New("models", outDir, WithCheck(&diff))
+	if err := gen.Generate(table); !errors.Is(err, ErrStale) {
+		t.Fatalf("Generate() error = %v, want ErrStale", err)
+	}
+	if !strings.Contains(diff.String(), "--- /dev/null\n") || !strings.Contains(diff.String(), "+type Users struct {") {
+		t.Errorf("diff should add the whole file, got:\n%s", diff.String())
+	}
+	if _, err := os.Stat(outDir); !os.IsNotExist(err) {
+		t.Error("check mode should not create the output directory")
+	}
+
+	if err := New("models", outDir).Generate(table); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	diff.Reset()
+	if err := New("models", outDir, WithCheck(&diff)).Generate(table); err != nil {
+		t.Errorf("Generate() on up-to-date file error = %v, want nil", err)
+	}
+	if diff.Len() != 0 {
+		t.Errorf("diff of up-to-date file = %q, want empty", diff.String())
+	}
+
+	// A file differing only in its final newline is stale too.
+	filePath := filepath.Join(outDir, "users.go")
+	content, _ := os.ReadFile(filePath)
+	if err := os.WriteFile(filePath, bytes.TrimSuffix(content, []byte("\n")), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	diff.Reset()
+	if err := New("models", outDir, WithCheck(&diff)).Generate(table); !errors.Is(err, ErrStale) {
+		t.Fatalf("Generate() on file without final newline error = %v, want ErrStale", err)
+	}
+	if !strings.Contains(diff.String(), "\\ No newline at end of file") {
+		t.Errorf("diff should report the missing final newline, got:\n%s", diff.String())
+	}
+	if err := os.WriteFile(filePath, content, 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	table.Columns[1].IsNullable = true
+	diff.Reset()
+	if err := New("models", outDir, WithCheck(&diff)).Generate(table); !errors.Is(err, ErrStale) {
+		t.Fatalf("Generate() error = %v, want ErrStale", err)
+	}
+	for _, want := range []string{"-\tName string `db:\"name\"`", "+\tName *string `db:\"name\"`"} {
+		if !strings.Contains(diff.String(), want) {
+			t.Errorf("diff should contain %q, got:\n%s", want, diff.String())
+		}
+	}
+	content, _ = os.ReadFile(filePath)
+	if strings.Contains(string(content), "*string") {
+		t.Error("check mode should not write files")
+	}
+}
+
+func TestGenerateCheckSharedFile(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "sqlgen_test")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	table := &schema.Table{
+		Name:    "users",
+		Columns: []schema.Column{{Name: "id", DataType: "int", ColumnKey: "PRI"}},
+	}
+	if err := New("models", tmpDir, WithCRUD(true)).Generate(table); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if err := os.Remove(filepath.Join(tmpDir, sharedFilename)); err != nil {
+		t.Fatalf("failed to remove shared file: %v", err)
+	}
+
+	var diff strings.Builder
+	if err := New("models", tmpDir, WithCRUD(true), WithCheck(&diff)).Generate(table); !errors.Is(err, ErrStale) {
+		t.Fatalf("Generate() error = %v, want ErrStale", err)
+	}
+	if !strings.Contains(diff.String(), "+++ "+filepath.Join(tmpDir, sharedFilename)) {
+		t.Errorf("diff should report the missing shared file, got:\n%s", diff.String())
+	}
+}
+
+func TestGenerateDryRun(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "sqlgen_test")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	// An existing file must neither be overwritten nor trigger the prompt.
+	existingFile := filepath.Join(tmpDir, "users.go")
+	if err := os.WriteFile(existingFile, []byte("existing content"), 0644); err != nil {
+		t.Fatalf("failed to create existing file: %v", err)
+	}
+	confirmCalled := false
+	confirm := func(string) bool {
+		confirmCalled = true
+		return true
+	}
+
+	var out strings.Builder
+	gen := New("models", tmpDir, WithDryRun(&out), WithConfirmFunc(confirm), WithCRUD(true))
+	for _, name := range []string{"users", "posts"} {
+		table := &schema.Table{
+			Name:    name,
+			Columns: []schema.Column{{Name: "id", DataType: "int", ColumnKey: "PRI"}},
+		}
+		if err := gen.Generate(table); err != nil {
+			t.Fatalf("Generate() error = %v", err)
+		}
+	}
+
+	got := out.String()
+	var headers []string
+	for _, line := range strings.Split(got, "\n") {
+		if strings.HasPrefix(line, "// ==> ") {
+			headers = append(headers, line)
+		}
+	}
+	want := []string{
+		"// ==> " + filepath.Join(tmpDir, sharedFilename) + " <==",
+		"// ==> " + filepath.Join(tmpDir, "users.go") + " <==",
+		"// ==> " + filepath.Join(tmpDir, "posts.go") + " <==",
+	}
+	if strings.Join(headers, "\n") != strings.Join(want, "\n") {
+		t.Errorf("file separators = %q, want %q", headers, want)
+	}
+	if !strings.Contains(got, "type Users struct") || !strings.Contains(got, "type Posts struct") {
+		t.Errorf("output should contain both structs, got:\n%s", got)
+	}
+
+	if confirmCalled {
+		t.Error("dry run should not ask for confirmation")
+	}
+	content, _ := os.ReadFile(existingFile)
+	if string(content) != "existing content" {
+		t.Error("dry run should not overwrite files")
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, "posts.go")); !os.IsNotExist(err) {
+		t.Error("dry run should not create files")
+	}
+
+	outDir := filepath.Join(tmpDir, "missing")
+	if err := New("models", outDir, WithDryRun(&out)).Generate(&schema.Table{Name: "users"}); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if _, err := os.Stat(outDir); !os.IsNotExist(err) {
+		t.Error("dry run should not create the output directory")
+	}
+}
+
+// runGenerated makes dir, holding generated code of package models, a module
+// and runs go vet and the given test file on it, which can exercise the
+// generated types. It is skipped in short mode or without a go command.
+func runGenerated(t *testing.T, dir, test string) {
+	t.Helper()
+	if testing.Short() {
+		t.Skip("skipping go command in short mode")
+	}
+	goCmd, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go command not found")
+	}
+	writeFiles(t, dir, map[string]string{
+		"go.mod":            "module models\n\ngo 1.22\n",
+		"generated_test.go": test,
+	})
+	for _, args := range [][]string{{"vet", "./..."}, {"test", "./..."}} {
+		cmd := exec.Command(goCmd, args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(), "GOWORK=off", "GOFLAGS=-mod=mod", "GOPROXY=off")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("go %s failed: %v\n%s", args[0], err, out)
+		}
+	}
+}
@@ -0,0 +1,155 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/ttaatoo/sqlgen/internal/schema"
+)
+
+// jsonWrapper is the generic type, declared in the shared file, that holds
+// the value of a column with a JSON type override.
+const jsonWrapper = "JSON"
+
+// rawJSONType is the type of JSON columns under the raw strategy, declared
+// in the shared file. Drivers bind a json.RawMessage as a binary string,
+// which MySQL refuses for JSON columns and lib/pq sends as bytea, so its
+// Value is a string instead.
+const rawJSONType = "RawJSON"
+
+// ParseJSON returns the Go type used for JSON columns by a JSON strategy:
+//
+//	string   string (the default)
+//	raw      RawJSON, a json.RawMessage bound as a string
+//
+// Without the raw strategy, PostgreSQL json and jsonb columns are
+// json.RawMessage.
+func ParseJSON(strategy string) (TypeOverride, error) {
+	switch strategy {
+	case "", "string":
+		return TypeOverride{}, nil
+	case "raw":
+		return TypeOverride{Type: rawJSONType}, nil
+	}
+	return TypeOverride{}, fmt.Errorf("invalid JSON strategy %q, want string or raw", strategy)
+}
+
+// jsonType returns the type of a field holding a value of type goType as
+// JSON, such as JSON[Settings].
+func jsonType(goType string) string {
+	return jsonWrapper + "[" + goType + "]"
+}
+
+// hasJSONOverrides reports whether a type override holds its type as JSON,
+// which needs the JSON type in the shared file.
+func (g *Generator) hasJSONOverrides() bool {
+	for _, o := range g.types {
+		if o.JSON {
+			return true
+		}
+	}
+	return false
+}
+
+// hasJSON reports whether a column of table has a JSON type override or
+// the raw JSON strategy, needing the shared file.
+func (g *Generator) hasJSON(table *schema.Table) bool {
+	for _, col := range table.Columns {
+		if o, ok := g.override(table, col); ok && (o.JSON || o.Type == rawJSONType) {
+			return true
+		}
+	}
+	return false
+}
+
+// generateJSON writes the JSON type to the shared file.
+func generateJSON(buf *bytes.Buffer) {
+	buf.WriteString("\n// JSON holds a value of type T stored as JSON in a column. Scan\n")
+	buf.WriteString("// unmarshals the column into V and Value marshals V.\n")
+	buf.WriteString("type JSON[T any] struct {\n")
+	buf.WriteString("\tV T\n")
+	buf.WriteString("}\n\n")
+
+	buf.WriteString("// Scan implements sql.Scanner. NULL leaves V the zero value of T.\n")
+	buf.WriteString("func (j *JSON[T]) Scan(src any) error {\n")
+	buf.WriteString("\tvar data []byte\n")
+	buf.WriteString("\tswitch value := src.(type) {\n")
+	buf.WriteString("\tcase nil:\n")
+	buf.WriteString("\t\t*j = JSON[T]{}\n")
+	buf.WriteString("\t\treturn nil\n")
+	buf.WriteString("\tcase []byte:\n")
+	buf.WriteString("\t\tdata = value\n")
+	buf.WriteString("\tcase string:\n")
+	buf.WriteString("\t\tdata = []byte(value)\n")
+	buf.WriteString("\tdefault:\n")
+	buf.WriteString("\t\treturn fmt.Errorf(\"cannot scan %T into JSON\", src)\n")
+	buf.WriteString("\t}\n")
+	buf.WriteString("\tvar v T\n")
+	buf.WriteString("\tif err := json.Unmarshal(data, &v); err != nil {\n")
+	buf.WriteString("\t\treturn fmt.Errorf(\"failed to unmarshal JSON: %w\", err)\n")
+	buf.WriteString("\t}\n")
+	buf.WriteString("\tj.V = v\n")
+	buf.WriteString("\treturn nil\n")
+	buf.WriteString("}\n\n")
+
+	buf.WriteString("// Value implements driver.Valuer. The JSON is returned as a string,\n")
+	buf.WriteString("// since MySQL refuses binary strings for JSON columns.\n")
+	buf.WriteString("func (j JSON[T]) Value() (driver.Value, error) {\n")
+	buf.WriteString("\tdata, err := json.Marshal(j.V)\n")
+	buf.WriteString("\tif err != nil {\n")
+	buf.WriteString("\t\treturn nil, fmt.Errorf(\"failed to marshal JSON: %w\", err)\n")
+	buf.WriteString("\t}\n")
+	buf.WriteString("\treturn string(data), nil\n")
+	buf.WriteString("}\n\n")
+
+	buf.WriteString("// MarshalJSON implements json.Marshaler, encoding V alone.\n")
+	buf.WriteString("func (j JSON[T]) MarshalJSON() ([]byte, error) {\n")
+	buf.WriteString("\treturn json.Marshal(j.V)\n")
+	buf.WriteString("}\n\n")
+
+	buf.WriteString("// UnmarshalJSON implements json.Unmarshaler, decoding into V.\n")
+	buf.WriteString("func (j *JSON[T]) UnmarshalJSON(data []byte) error {\n")
+	buf.WriteString("\treturn json.Unmarshal(data, &j.V)\n")
+	buf.WriteString("}\n")
+}
+
+// generateRawJSON writes the RawJSON type to the shared file.
+func generateRawJSON(buf *bytes.Buffer) {
+	buf.WriteString("\n// RawJSON is a JSON column as raw JSON text. Unlike json.RawMessage, which\n")
+	buf.WriteString("// drivers bind as a binary string, its Value is a string, which MySQL and\n")
+	buf.WriteString("// PostgreSQL accept for JSON columns. A nil RawJSON is NULL.\n")
+	buf.WriteString("type RawJSON json.RawMessage\n\n")
+
+	buf.WriteString("// Scan implements sql.Scanner.\n")
+	buf.WriteString("func (r *RawJSON) Scan(src any) error {\n")
+	buf.WriteString("\tswitch value := src.(type) {\n")
+	buf.WriteString("\tcase nil:\n")
+	buf.WriteString("\t\t*r = nil\n")
+	buf.WriteString("\tcase []byte:\n")
+	buf.WriteString("\t\t*r = append(RawJSON(nil), value...)\n")
+	buf.WriteString("\tcase string:\n")
+	buf.WriteString("\t\t*r = RawJSON(value)\n")
+	buf.WriteString("\tdefault:\n")
+	buf.WriteString("\t\treturn fmt.Errorf(\"cannot scan %T into RawJSON\", src)\n")
+	buf.WriteString("\t}\n")
+	buf.WriteString("\treturn nil\n")
+	buf.WriteString("}\n\n")
+
+	buf.WriteString("// Value implements driver.Valuer.\n")
+	buf.WriteString("func (r RawJSON) Value() (driver.Value, error) {\n")
+	buf.WriteString("\tif r == nil {\n")
+	buf.WriteString("\t\treturn nil, nil\n")
+	buf.WriteString("\t}\n")
+	buf.WriteString("\treturn string(r), nil\n")
+	buf.WriteString("}\n\n")
+
+	buf.WriteString("// MarshalJSON implements json.Marshaler, encoding r as is.\n")
+	buf.WriteString("func (r RawJSON) MarshalJSON() ([]byte, error) {\n")
+	buf.WriteString("\treturn json.RawMessage(r).MarshalJSON()\n")
+	buf.WriteString("}\n\n")
+
+	buf.WriteString("// UnmarshalJSON implements json.Unmarshaler, keeping a copy of data.\n")
+	buf.WriteString("func (r *RawJSON) UnmarshalJSON(data []byte) error {\n")
+	buf.WriteString("\treturn (*json.RawMessage)(r).UnmarshalJSON(data)\n")
+	buf.WriteString("}\n")
+}
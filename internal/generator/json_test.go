@@ -0,0 +1,189 @@
+package generator
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseJSON(t *testing.T) {
+	tests := []struct {
+		strategy string
+		want     TypeOverride
+		wantErr  bool
+	}{
+		{"", TypeOverride{}, false},
+		{"string", TypeOverride{}, false},
+		{"raw", TypeOverride{Type: "RawJSON"}, false},
+		{"struct", TypeOverride{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.strategy, func(t *testing.T) {
+			got, err := ParseJSON(tt.strategy)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseJSON() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseJSON() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// jsonOverrides hold the settings and tags columns of the json test table
+// as JSON.
+var jsonOverrides = map[string]TypeOverride{
+	"users.settings": {Type: "Settings", JSON: true},
+	"users.tags":     {Type: "[]string", JSON: true},
+}
+
+func TestJSONGoType(t *testing.T) {
+	raw, _ := ParseJSON("raw")
+	tests := []struct {
+		name string
+		opts []Option
+		want []string
+	}{
+		{"default", nil, []string{"int32", "string", "*string", "*string", "string"}},
+		{"raw", []Option{WithJSON(raw)}, []string{"int32", "RawJSON", "RawJSON", "RawJSON", "string"}},
+		{"overrides", []Option{WithJSON(raw), WithTypeOverrides(jsonOverrides)}, []string{"int32", "JSON[Settings]", "*JSON[[]string]", "RawJSON", "string"}},
+		{"sql", []Option{WithTypeOverrides(jsonOverrides), WithNullable(NullSQL)}, []string{"int32", "JSON[Settings]", "sql.Null[JSON[[]string]]", "sql.NullString", "string"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gen := New("models", "/tmp/output", tt.opts...)
+			table := testTable("json")
+			for i, col := range table.Columns {
+				if got := gen.goType(table, col); got != tt.want[i] {
+					t.Errorf("goType(%s) = %q, want %q", col.Name, got, tt.want[i])
+				}
+			}
+		})
+	}
+
+	gen := New("models", "/tmp/output", WithTypeOverrides(map[string]TypeOverride{
+		"users.settings": {Type: "settings.Settings", Import: "example.com/app/settings", JSON: true},
+	}))
+	if imports, want := gen.collectImports(testTable("json")), []string{"example.com/app/settings"}; !reflect.DeepEqual(imports, want) {
+		t.Errorf("collectImports() = %v, want %v", imports, want)
+	}
+}
+
+func TestGenerateJSONSharedFile(t *testing.T) {
+	raw, _ := ParseJSON("raw")
+	tests := []struct {
+		name string
+		opts []Option
+		want []string
+	}{
+		{
+			name: "overrides",
+			opts: []Option{WithTypeOverrides(jsonOverrides)},
+			want: []string{
+				"type JSON[T any] struct {\n\tV T\n}",
+				"func (j *JSON[T]) Scan(src any) error {",
+				"func (j JSON[T]) Value() (driver.Value, error) {",
+				"func (j JSON[T]) MarshalJSON() ([]byte, error) {",
+				"func (j *JSON[T]) UnmarshalJSON(data []byte) error {",
+			},
+		},
+		{
+			name: "raw",
+			opts: []Option{WithJSON(raw)},
+			want: []string{
+				"type RawJSON json.RawMessage",
+				"func (r *RawJSON) Scan(src any) error {",
+				"func (r RawJSON) Value() (driver.Value, error) {",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content := sharedFile(t, testTable("json"), tt.opts...)
+			want := append([]string{"import (\n\t\"database/sql/driver\"\n\t\"encoding/json\"\n\t\"fmt\"\n)"}, tt.want...)
+			for _, want := range want {
+				if !strings.Contains(content, want) {
+					t.Errorf("shared file should contain %q\n%s", want, content)
+				}
+			}
+		})
+	}
+}
+
+func TestJSONScanValue(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "sqlgen_test")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	raw, _ := ParseJSON("raw")
+	gen := New("models", tmpDir, WithCRUD(true), WithJSON(raw), WithTypeOverrides(jsonOverrides))
+	if err := gen.Generate(testTable("json")); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	writeFiles(t, tmpDir, map[string]string{
+		"settings.go": "package models\n\ntype Settings struct {\n\tTheme string `json:\"theme\"`\n}\n",
+	})
+
+	// MySQL refuses binary strings for JSON columns, so JSON values must
+	// reach the driver as strings.
+	runGenerated(t, tmpDir, `package models
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestJSON(t *testing.T) {
+	u := Users{Settings: JSON[Settings]{V: Settings{Theme: "dark"}}}
+	v, err := u.Settings.Value()
+	if s, ok := v.(string); !ok || s != `+"`"+`{"theme":"dark"}`+"`"+` || err != nil {
+		t.Fatalf("Value() = %#v, %v, want a string", v, err)
+	}
+	var got JSON[Settings]
+	if err := got.Scan([]byte(v.(string))); err != nil || got != u.Settings {
+		t.Errorf("Scan() = %+v, %v, want %+v", got, err, u.Settings)
+	}
+	if err := got.Scan(nil); err != nil || got != (JSON[Settings]{}) {
+		t.Errorf("Scan(nil) = %+v, %v, want the zero value", got, err)
+	}
+
+	u.Tags = &JSON[[]string]{V: []string{"a"}}
+	u.Extra = RawJSON("1")
+	data, err := json.Marshal(u)
+	if want := `+"`"+`{"ID":0,"Settings":{"theme":"dark"},"Tags":["a"],"Extra":1,"Notes":""}`+"`"+`; string(data) != want || err != nil {
+		t.Fatalf("json.Marshal() = %s, %v, want %s", data, err, want)
+	}
+	var back Users
+	if err := json.Unmarshal(data, &back); err != nil || !reflect.DeepEqual(back, u) {
+		t.Errorf("json.Unmarshal() = %+v, %v, want %+v", back, err, u)
+	}
+}
+
+func TestRawJSON(t *testing.T) {
+	r := RawJSON(`+"`"+`{"a":1}`+"`"+`)
+	v, err := r.Value()
+	if s, ok := v.(string); !ok || s != `+"`"+`{"a":1}`+"`"+` || err != nil {
+		t.Errorf("Value() = %#v, %v, want a string", v, err)
+	}
+	if v, err := RawJSON(nil).Value(); v != nil || err != nil {
+		t.Errorf("Value() of nil RawJSON = %#v, %v, want NULL", v, err)
+	}
+
+	src := []byte("[1]")
+	if err := r.Scan(src); err != nil || string(r) != "[1]" {
+		t.Fatalf("Scan() = %v, %s", err, r)
+	}
+	src[1] = '2'
+	if string(r) != "[1]" {
+		t.Errorf("Scan() should copy the driver's bytes, got %s", r)
+	}
+}
+`)
+}
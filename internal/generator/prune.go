@@ -0,0 +1,88 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// sqlgenMarker starts the header of every file sqlgen generates, which
+// distinguishes them from files generated by other tools.
+const sqlgenMarker = "// Code generated by sqlgen"
+
+// Keep marks the files of tables as current, so that Prune leaves them
+// alone even though Generate did not produce them this run, such as the
+// files of tables that still exist but were filtered out.
+func (g *Generator) Keep(tables ...string) {
+	for _, table := range tables {
+		g.kept[toSnakeCase(table)+".go"] = true
+	}
+}
+
+// Prune finds the files in the output directory that sqlgen generated but
+// that no call to Generate produced this run and Keep did not mark, such as
+// the files of dropped tables, and removes them. It returns their paths in
+// order.
+//
+// The shared file is kept as long as a file that Keep marked remains
+// without being generated this run, since that file may use it.
+//
+// In check and dry-run mode nothing is removed; the paths are only
+// reported, and in check mode Prune returns ErrStale if there are any.
+func (g *Generator) Prune() ([]string, error) {
+	entries, err := os.ReadDir(g.outputDir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list output directory: %w", err)
+	}
+
+	var stale []string
+	keepShared := false
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || filepath.Ext(name) != ".go" || strings.HasSuffix(name, "_test.go") || g.generated[name] {
+			continue
+		}
+		if g.kept[name] {
+			keepShared = true
+			continue
+		}
+		path := filepath.Join(g.outputDir, name)
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return stale, fmt.Errorf("failed to read file: %w", err)
+		}
+		if !isGenerated(content) {
+			continue
+		}
+		stale = append(stale, path)
+	}
+	if keepShared {
+		stale = slices.DeleteFunc(stale, func(path string) bool {
+			return filepath.Base(path) == sharedFilename
+		})
+	}
+	sort.Strings(stale)
+
+	switch {
+	case g.checkOut != nil:
+		if len(stale) > 0 {
+			return stale, ErrStale
+		}
+	case g.dryRunOut != nil:
+	default:
+		for i, path := range stale {
+			if err := os.Remove(path); err != nil {
+				return stale[:i], fmt.Errorf("failed to remove file: %w", err)
+			}
+		}
+	}
+	return stale, nil
+}
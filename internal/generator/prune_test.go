@@ -0,0 +1,158 @@
+package generator
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/ttaatoo/sqlgen/internal/schema"
+)
+
+// pruneDir creates an output directory holding the files of an earlier run
+// that generated users, orders and the shared file, next to files sqlgen
+// must never remove.
+func pruneDir(t *testing.T) string {
+	t.Helper()
+	dir, err := os.MkdirTemp("", "sqlgen_test")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	writeFiles(t, dir, map[string]string{
+		"users.go":       generatedFile,
+		"orders.go":      generatedFile,
+		sharedFilename:   generatedFile,
+		"helpers.go":     "package models\n",
+		"enum_string.go": "// Code generated by \"stringer -type=Status\"; DO NOT EDIT.\n\npackage models\n",
+		"orders_test.go": generatedFile,
+		"notes.txt":      generatedFile,
+		"late_marker.go": "package models\n\n// Code generated by sqlgen. DO NOT EDIT.\n",
+		"unparseable.go": "// Code generated by sqlgen. DO NOT EDIT.\n\npackage",
+	})
+	return dir
+}
+
+func TestPrune(t *testing.T) {
+	dir := pruneDir(t)
+	defer os.RemoveAll(dir)
+
+	// orders was dropped and CRUD turned off, so only users is generated.
+	gen := New("models", dir, WithForce(true))
+	users := &schema.Table{Name: "users", Columns: []schema.Column{{Name: "id", DataType: "int"}}}
+	if err := gen.Generate(users); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	removed, err := gen.Prune()
+	if err != nil {
+		t.Fatalf("Prune() error = %v", err)
+	}
+	want := []string{filepath.Join(dir, "orders.go"), filepath.Join(dir, sharedFilename)}
+	if !reflect.DeepEqual(removed, want) {
+		t.Errorf("Prune() = %v, want %v", removed, want)
+	}
+
+	for _, name := range []string{"orders.go", sharedFilename} {
+		if _, err := os.Stat(filepath.Join(dir, name)); !errors.Is(err, os.ErrNotExist) {
+			t.Errorf("%s should have been removed", name)
+		}
+	}
+	for _, name := range []string{"users.go", "helpers.go", "enum_string.go", "orders_test.go", "notes.txt", "late_marker.go", "unparseable.go"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Errorf("%s should have been kept: %v", name, err)
+		}
+	}
+}
+
+func TestPruneKeepsExcludedTables(t *testing.T) {
+	dir := pruneDir(t)
+	defer os.RemoveAll(dir)
+
+	// orders still exists but is excluded, so only users is generated.
+	gen := New("models", dir, WithForce(true), WithCRUD(true))
+	gen.Keep("users", "orders")
+	users := &schema.Table{Name: "users", Columns: []schema.Column{{Name: "id", DataType: "int"}}}
+	if err := gen.Generate(users); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	removed, err := gen.Prune()
+	if err != nil || len(removed) != 0 {
+		t.Errorf("Prune() = %v, %v, want nothing to remove", removed, err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "orders.go")); err != nil {
+		t.Errorf("orders.go of the excluded table should have been kept: %v", err)
+	}
+}
+
+func TestPruneKeepsSharedFileOfExcludedTables(t *testing.T) {
+	dir := pruneDir(t)
+	defer os.RemoveAll(dir)
+
+	// orders is generated without needing the shared file, but the kept
+	// users.go of an excluded table may still use it.
+	gen := New("models", dir, WithForce(true))
+	gen.Keep("users", "orders")
+	orders := &schema.Table{Name: "orders", Columns: []schema.Column{{Name: "id", DataType: "int"}}}
+	if err := gen.Generate(orders); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	removed, err := gen.Prune()
+	if err != nil || len(removed) != 0 {
+		t.Errorf("Prune() = %v, %v, want nothing to remove", removed, err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, sharedFilename)); err != nil {
+		t.Errorf("%s used by kept files should have been kept: %v", sharedFilename, err)
+	}
+}
+
+func TestPruneCheckAndDryRun(t *testing.T) {
+	tests := []struct {
+		name    string
+		opt     Option
+		wantErr error
+	}{
+		{"check", WithCheck(io.Discard), ErrStale},
+		{"dry-run", WithDryRun(io.Discard), nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := pruneDir(t)
+			defer os.RemoveAll(dir)
+
+			gen := New("models", dir, tt.opt, WithCRUD(true))
+			for _, name := range []string{"users", "orders"} {
+				table := &schema.Table{Name: name, Columns: []schema.Column{{Name: "id", DataType: "int"}}}
+				gen.Generate(table)
+			}
+			if removed, err := gen.Prune(); err != nil || len(removed) != 0 {
+				t.Errorf("Prune() = %v, %v, want nothing to remove", removed, err)
+			}
+
+			gen = New("models", dir, tt.opt)
+			removed, err := gen.Prune()
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Prune() error = %v, want %v", err, tt.wantErr)
+			}
+			if len(removed) != 3 {
+				t.Errorf("Prune() = %v, want the three generated files", removed)
+			}
+			for _, path := range removed {
+				if _, err := os.Stat(path); err != nil {
+					t.Errorf("%s should not have been removed: %v", path, err)
+				}
+			}
+		})
+	}
+}
+
+func TestPruneMissingDirectory(t *testing.T) {
+	gen := New("models", filepath.Join(os.TempDir(), "sqlgen_test_missing"))
+	if removed, err := gen.Prune(); err != nil || removed != nil {
+		t.Errorf("Prune() = %v, %v, want nil, nil", removed, err)
+	}
+}
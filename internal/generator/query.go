@@ -0,0 +1,155 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/ttaatoo/sqlgen/internal/schema"
+)
+
+// sharedFilename is the file holding helpers shared by the query code of
+// all tables, the Bit type of the boolean mapping and the JSON and RawJSON
+// types of JSON columns.
+const sharedFilename = "sqlgen.go"
+
+// needsShared reports whether the enabled options generate code for table
+// that uses the shared file.
+func (g *Generator) needsShared(table *schema.Table) bool {
+	return g.relations || g.crud || g.hasBit(table) || g.hasJSON(table)
+}
+
+// generateShared returns the shared file: the DBTX interface that generated
+// queries run against and a placeholder builder for the dialect if queries
+// are generated, the Bit type if booleans are mapped, the JSON type if a
+// type override holds its type as JSON and the RawJSON type under the raw
+// JSON strategy.
+func (g *Generator) generateShared(dialect schema.Dialect) string {
+	var buf bytes.Buffer
+	queries := g.relations || g.crud
+	jsonTypes := g.hasJSONOverrides()
+	rawJSON := g.json.Type == rawJSONType
+
+	var imports []string
+	if queries {
+		imports = append(imports, "context", "database/sql", "strings")
+		if dialect == schema.Postgres {
+			imports = append(imports, "strconv")
+		}
+	}
+	if g.bools {
+		imports = append(imports, "database/sql/driver", "fmt")
+	}
+	if jsonTypes || rawJSON {
+		imports = append(imports, "database/sql/driver", "encoding/json", "fmt")
+	}
+	slices.Sort(imports)
+	imports = slices.Compact(imports)
+
+	buf.WriteString(fmt.Sprintf("package %s\n\n", g.packageName))
+
+	buf.WriteString("import (\n")
+	for _, path := range imports {
+		buf.WriteString(fmt.Sprintf("\t%q\n", path))
+	}
+	buf.WriteString(")\n")
+
+	if queries {
+		generateQueryHelpers(&buf, dialect)
+	}
+	if g.bools {
+		generateBit(&buf)
+	}
+	if jsonTypes {
+		generateJSON(&buf)
+	}
+	if rawJSON {
+		generateRawJSON(&buf)
+	}
+
+	return buf.String()
+}
+
+// generateQueryHelpers writes the DBTX interface and placeholder builder to
+// the shared file.
+func generateQueryHelpers(buf *bytes.Buffer, dialect schema.Dialect) {
+	buf.WriteString("\n// DBTX is the database handle used by generated queries. It is\n")
+	buf.WriteString("// implemented by *sql.DB, *sql.Tx and *sql.Conn.\n")
+	buf.WriteString("type DBTX interface {\n")
+	buf.WriteString("\tExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)\n")
+	buf.WriteString("\tQueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)\n")
+	buf.WriteString("\tQueryRowContext(ctx context.Context, query string, args ...any) *sql.Row\n")
+	buf.WriteString("}\n\n")
+
+	buf.WriteString("// placeholders returns n comma-separated bind parameters, numbered from\n")
+	buf.WriteString("// start where the dialect numbers them.\n")
+	buf.WriteString("func placeholders(start, n int) string {\n")
+	if dialect == schema.Postgres {
+		buf.WriteString("\tparams := make([]string, n)\n")
+		buf.WriteString("\tfor i := range params {\n")
+		buf.WriteString("\t\tparams[i] = \"$\" + strconv.Itoa(start+i)\n")
+		buf.WriteString("\t}\n")
+		buf.WriteString("\treturn strings.Join(params, \", \")\n")
+	} else {
+		buf.WriteString("\treturn strings.TrimSuffix(strings.Repeat(\"?, \", n), \", \")\n")
+	}
+	buf.WriteString("}\n")
+}
+
+// quoteIdent quotes a table or column name for the dialect.
+func quoteIdent(dialect schema.Dialect, name string) string {
+	switch dialect {
+	case schema.Postgres, schema.SQLite:
+		return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+	default:
+		return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+	}
+}
+
+// columnList returns the table's quoted column names, comma-separated.
+func columnList(table *schema.Table) string {
+	names := make([]string, len(table.Columns))
+	for i, col := range table.Columns {
+		names[i] = quoteIdent(table.Dialect, col.Name)
+	}
+	return strings.Join(names, ", ")
+}
+
+// scanArgs returns the addresses of every field of the struct variable v,
+// in column order, for rows.Scan.
+func (g *Generator) scanArgs(table *schema.Table, v string) string {
+	args := make([]string, len(table.Columns))
+	for i, col := range table.Columns {
+		args[i] = "&" + v + "." + g.camel(col.Name)
+	}
+	return strings.Join(args, ", ")
+}
+
+// receiverName returns the receiver name used for methods on structName.
+func receiverName(structName string) string {
+	for _, r := range structName {
+		if unicode.IsLetter(r) {
+			return string(unicode.ToLower(r))
+		}
+		break
+	}
+	return "r"
+}
+
+// fieldValue returns an expression for the non-null value of the field of
+// col, and a condition that is true when the field holds NULL, or "" for
+// fields that are never NULL.
+func (g *Generator) fieldValue(table *schema.Table, col schema.Column, field string) (value, isNull string) {
+	if !col.IsNullable {
+		return field, ""
+	}
+	return nullValue(g.goType(table, col), field)
+}
+
+// baseType returns the Go type of col ignoring its nullability.
+func (g *Generator) baseType(table *schema.Table, col schema.Column) string {
+	col.IsNullable = false
+	return g.goType(table, col)
+}
@@ -0,0 +1,165 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/ttaatoo/sqlgen/internal/schema"
+)
+
+// Relations are generated for single-column foreign keys. Each table gets a
+// finder per column that takes part in one, such as OrdersByUserIDs, which
+// the loader methods of the related tables call:
+//
+//	func (o *Orders) LoadUser(ctx context.Context, db DBTX) (*Users, error)
+//	func (u *Users) LoadOrders(ctx context.Context, db DBTX) ([]*Orders, error)
+
+func singleColumn(fks []schema.ForeignKey) []schema.ForeignKey {
+	var result []schema.ForeignKey
+	for _, fk := range fks {
+		if len(fk.Columns) == 1 && len(fk.RefColumns) == 1 {
+			result = append(result, fk)
+		}
+	}
+	return result
+}
+
+// relationImports returns the imports used by the relation code of table.
+func relationImports(table *schema.Table) []string {
+	belongsTo := singleColumn(table.ForeignKeys)
+	hasMany := singleColumn(table.ReferencedBy)
+	if len(belongsTo) == 0 && len(hasMany) == 0 {
+		return nil
+	}
+	if len(belongsTo) > 0 {
+		return []string{"context", "database/sql"}
+	}
+	return []string{"context"}
+}
+
+// finderName returns the name of the function looking up rows of table by
+// column, such as UsersByIDs.
+func (g *Generator) finderName(table, column string) string {
+	return g.camel(table) + "By" + g.camel(column) + "s"
+}
+
+// belongsToName returns the loader name for a foreign key column: the
+// column without its _id suffix, such as LoadUser for user_id.
+func (g *Generator) belongsToName(column string) string {
+	name := column
+	if len(name) > 3 && strings.EqualFold(name[len(name)-3:], "_id") {
+		name = name[:len(name)-3]
+	}
+	return "Load" + g.camel(name)
+}
+
+// hasManyNames returns the loader name for each key referencing the table:
+// the referencing table, such as LoadOrders, qualified by the column when
+// one table references it more than once.
+func (g *Generator) hasManyNames(fks []schema.ForeignKey) []string {
+	counts := make(map[string]int)
+	for _, fk := range fks {
+		counts[fk.Table]++
+	}
+	names := make([]string, len(fks))
+	for i, fk := range fks {
+		names[i] = "Load" + g.camel(fk.Table)
+		if counts[fk.Table] > 1 {
+			names[i] += "By" + g.camel(fk.Columns[0])
+		}
+	}
+	return names
+}
+
+func (g *Generator) generateRelations(buf *bytes.Buffer, table *schema.Table) {
+	belongsTo := singleColumn(table.ForeignKeys)
+	hasMany := singleColumn(table.ReferencedBy)
+
+	// Finders for this table's side of every relation, in column order.
+	lookup := make(map[string]bool)
+	for _, fk := range belongsTo {
+		lookup[strings.ToLower(fk.Columns[0])] = true
+	}
+	for _, fk := range hasMany {
+		lookup[strings.ToLower(fk.RefColumns[0])] = true
+	}
+	for _, col := range table.Columns {
+		if lookup[strings.ToLower(col.Name)] {
+			g.generateFinder(buf, table, col)
+		}
+	}
+
+	structName := g.camel(table.Name)
+	recv := receiverName(structName)
+
+	for _, fk := range belongsTo {
+		col := table.Column(fk.Columns[0])
+		if col == nil {
+			continue
+		}
+		refStruct := g.camel(fk.RefTable)
+		value, isNull := g.fieldValue(table, *col, recv+"."+g.camel(col.Name))
+
+		buf.WriteString(fmt.Sprintf("\n// %s returns the %s row referenced by %s.\n", g.belongsToName(col.Name), fk.RefTable, col.Name))
+		buf.WriteString(fmt.Sprintf("func (%s *%s) %s(ctx context.Context, db DBTX) (*%s, error) {\n",
+			recv, structName, g.belongsToName(col.Name), refStruct))
+		if isNull != "" {
+			buf.WriteString(fmt.Sprintf("\tif %s {\n\t\treturn nil, nil\n\t}\n", isNull))
+		}
+		buf.WriteString(fmt.Sprintf("\trows, err := %s(ctx, db, %s)\n", g.finderName(fk.RefTable, fk.RefColumns[0]), value))
+		buf.WriteString("\tif err != nil {\n\t\treturn nil, err\n\t}\n")
+		buf.WriteString("\tif len(rows) == 0 {\n\t\treturn nil, sql.ErrNoRows\n\t}\n")
+		buf.WriteString("\treturn rows[0], nil\n")
+		buf.WriteString("}\n")
+	}
+
+	names := g.hasManyNames(hasMany)
+	for i, fk := range hasMany {
+		col := table.Column(fk.RefColumns[0])
+		if col == nil {
+			continue
+		}
+		name := names[i]
+		refStruct := g.camel(fk.Table)
+		value, isNull := g.fieldValue(table, *col, recv+"."+g.camel(col.Name))
+
+		buf.WriteString(fmt.Sprintf("\n// %s returns the %s rows whose %s references this row.\n", name, fk.Table, fk.Columns[0]))
+		buf.WriteString(fmt.Sprintf("func (%s *%s) %s(ctx context.Context, db DBTX) ([]*%s, error) {\n",
+			recv, structName, name, refStruct))
+		if isNull != "" {
+			buf.WriteString(fmt.Sprintf("\tif %s {\n\t\treturn nil, nil\n\t}\n", isNull))
+		}
+		buf.WriteString(fmt.Sprintf("\treturn %s(ctx, db, %s)\n", g.finderName(fk.Table, fk.Columns[0]), value))
+		buf.WriteString("}\n")
+	}
+}
+
+// generateFinder writes the function returning the rows of table whose col
+// is one of the given values.
+func (g *Generator) generateFinder(buf *bytes.Buffer, table *schema.Table, col schema.Column) {
+	structName := g.camel(table.Name)
+	name := g.finderName(table.Name, col.Name)
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE %s IN (",
+		columnList(table), quoteIdent(table.Dialect, table.Name), quoteIdent(table.Dialect, col.Name))
+
+	buf.WriteString(fmt.Sprintf("\n// %s returns the %s rows whose %s is one of values.\n", name, table.Name, col.Name))
+	buf.WriteString(fmt.Sprintf("func %s(ctx context.Context, db DBTX, values ...%s) ([]*%s, error) {\n",
+		name, g.baseType(table, col), structName))
+	buf.WriteString("\tif len(values) == 0 {\n\t\treturn nil, nil\n\t}\n")
+	buf.WriteString("\targs := make([]any, len(values))\n")
+	buf.WriteString("\tfor i, v := range values {\n\t\targs[i] = v\n\t}\n")
+	buf.WriteString(fmt.Sprintf("\tquery := %s + placeholders(1, len(args)) + \")\"\n", strconv.Quote(query)))
+	buf.WriteString("\trows, err := db.QueryContext(ctx, query, args...)\n")
+	buf.WriteString("\tif err != nil {\n\t\treturn nil, err\n\t}\n")
+	buf.WriteString("\tdefer rows.Close()\n\n")
+	buf.WriteString(fmt.Sprintf("\tvar result []*%s\n", structName))
+	buf.WriteString("\tfor rows.Next() {\n")
+	buf.WriteString(fmt.Sprintf("\t\tvar row %s\n", structName))
+	buf.WriteString(fmt.Sprintf("\t\tif err := rows.Scan(%s); err != nil {\n\t\t\treturn nil, err\n\t\t}\n", g.scanArgs(table, "row")))
+	buf.WriteString("\t\tresult = append(result, &row)\n")
+	buf.WriteString("\t}\n")
+	buf.WriteString("\treturn result, rows.Err()\n")
+	buf.WriteString("}\n")
+}
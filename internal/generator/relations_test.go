@@ -0,0 +1,183 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ttaatoo/sqlgen/internal/schema"
+)
+
+func TestGenerateRelations(t *testing.T) {
+	gen := New("models", "/tmp/output", WithRelations(true))
+	users, orders := testTable("users"), testTable("orders")
+
+	code := mustRender(t, gen, orders)
+	for _, want := range []string{
+		`"context"`,
+		`"database/sql"`,
+		"func OrdersByIDs(ctx context.Context, db DBTX, values ...int64) ([]*Orders, error)",
+		"func OrdersByUserIDs(ctx context.Context, db DBTX, values ...int64) ([]*Orders, error)",
+		"func OrdersByParentIDs(ctx context.Context, db DBTX, values ...int64) ([]*Orders, error)",
+		"\"SELECT `id`, `user_id`, `parent_id` FROM `orders` WHERE `user_id` IN (\" + placeholders(1, len(args)) + \")\"",
+		"rows.Scan(&row.ID, &row.UserID, &row.ParentID)",
+		"func (o *Orders) LoadUser(ctx context.Context, db DBTX) (*Users, error)",
+		"UsersByIDs(ctx, db, o.UserID)",
+		"func (o *Orders) LoadParent(ctx context.Context, db DBTX) (*Orders, error)",
+		"if o.ParentID == nil {",
+		"OrdersByIDs(ctx, db, *o.ParentID)",
+		"func (o *Orders) LoadOrders(ctx context.Context, db DBTX) ([]*Orders, error)",
+		"OrdersByParentIDs(ctx, db, o.ID)",
+	} {
+		if !strings.Contains(code, want) {
+			t.Errorf("generated code should contain %q\n%s", want, code)
+		}
+	}
+	if strings.Contains(code, "LoadPairs") || strings.Contains(code, "PairsBy") {
+		t.Error("generated code should skip composite foreign keys")
+	}
+
+	code = mustRender(t, gen, users)
+	for _, want := range []string{
+		"func UsersByIDs(ctx context.Context, db DBTX, values ...int64) ([]*Users, error)",
+		"func (u *Users) LoadOrders(ctx context.Context, db DBTX) ([]*Orders, error)",
+		"OrdersByUserIDs(ctx, db, u.ID)",
+	} {
+		if !strings.Contains(code, want) {
+			t.Errorf("generated code should contain %q\n%s", want, code)
+		}
+	}
+	if strings.Contains(code, `"database/sql"`) {
+		t.Error("has-many only code should not import database/sql")
+	}
+}
+
+func TestGenerateRelationsNullable(t *testing.T) {
+	tests := []struct {
+		nullable Nullable
+		want     []string
+	}{
+		{NullSQL, []string{"ParentID sql.NullInt64", "if !o.ParentID.Valid {", "OrdersByIDs(ctx, db, o.ParentID.Int64)"}},
+		{NullGeneric, []string{"ParentID sql.Null[int64]", "if !o.ParentID.Valid {", "OrdersByIDs(ctx, db, o.ParentID.V)"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.nullable), func(t *testing.T) {
+			gen := New("models", "/tmp/output", WithRelations(true), WithNullable(tt.nullable))
+			orders := testTable("orders")
+			code := mustRender(t, gen, orders)
+			for _, want := range tt.want {
+				if !strings.Contains(code, want) {
+					t.Errorf("generated code should contain %q\n%s", want, code)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateRelationsDisabled(t *testing.T) {
+	gen := New("models", "/tmp/output")
+	orders := testTable("orders")
+
+	code := mustRender(t, gen, orders)
+	if strings.Contains(code, "LoadUser") || strings.Contains(code, `"context"`) {
+		t.Errorf("relations should not be generated by default\n%s", code)
+	}
+}
+
+func TestHasManyNames(t *testing.T) {
+	fks := []schema.ForeignKey{
+		{Table: "orders", Columns: []string{"buyer_id"}},
+		{Table: "orders", Columns: []string{"seller_id"}},
+		{Table: "reviews", Columns: []string{"user_id"}},
+	}
+	got := strings.Join(New("models", "/tmp/output").hasManyNames(fks), ",")
+	if want := "LoadOrdersByBuyerID,LoadOrdersBySellerID,LoadReviews"; got != want {
+		t.Errorf("hasManyNames() = %q, want %q", got, want)
+	}
+}
+
+func TestBelongsToName(t *testing.T) {
+	tests := []struct {
+		column string
+		want   string
+	}{
+		{"user_id", "LoadUser"},
+		{"parent_ID", "LoadParent"},
+		{"owner", "LoadOwner"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.column, func(t *testing.T) {
+			if got := New("models", "/tmp/output").belongsToName(tt.column); got != tt.want {
+				t.Errorf("belongsToName(%q) = %q, want %q", tt.column, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerateRelationsWithKeyOverrides(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "sqlgen_test")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	// Only the referenced keys are overridden; the foreign key columns,
+	// including the nullable parent_id, take their types.
+	types := map[string]TypeOverride{"users.id": {Type: "UserID"}, "orders.id": {Type: "OrderID"}}
+	gen := New("models", tmpDir, WithRelations(true), WithCRUD(true), WithNullable(NullSQL), WithTypeOverrides(types))
+	users, orders := testTable("users"), testTable("orders")
+	if got := gen.goType(orders, orders.Columns[1]); got != "UserID" {
+		t.Errorf("goType(user_id) = %q, want UserID", got)
+	}
+	for _, table := range []*schema.Table{users, orders} {
+		if err := gen.Generate(table); err != nil {
+			t.Fatalf("Generate() error = %v", err)
+		}
+	}
+
+	ids := "package models\n\ntype (\n\tUserID int64\n\tOrderID int64\n)\n"
+	if err := os.WriteFile(filepath.Join(tmpDir, "ids.go"), []byte(ids), 0644); err != nil {
+		t.Fatalf("failed to write ids.go: %v", err)
+	}
+	runGenerated(t, tmpDir, "package models\n")
+}
+
+func TestGenerateWritesSharedFile(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "sqlgen_test")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	gen := New("models", tmpDir, WithRelations(true))
+	users, orders := testTable("users"), testTable("orders")
+	users.Dialect = schema.Postgres
+	orders.Dialect = schema.Postgres
+
+	for _, table := range []*schema.Table{users, orders} {
+		if err := gen.Generate(table); err != nil {
+			t.Fatalf("Generate() error = %v", err)
+		}
+	}
+
+	content, err := os.ReadFile(filepath.Join(tmpDir, sharedFilename))
+	if err != nil {
+		t.Fatalf("failed to read shared file: %v", err)
+	}
+	for _, want := range []string{"type DBTX interface", "func placeholders(start, n int) string", `"$" + strconv.Itoa(start+i)`} {
+		if !strings.Contains(string(content), want) {
+			t.Errorf("shared file should contain %q\n%s", want, content)
+		}
+	}
+
+	content, err = os.ReadFile(filepath.Join(tmpDir, "orders.go"))
+	if err != nil {
+		t.Fatalf("failed to read generated file: %v", err)
+	}
+	if want := `"SELECT \"id\", \"user_id\", \"parent_id\" FROM \"orders\" WHERE \"id\" IN ("`; !strings.Contains(string(content), want) {
+		t.Errorf("generated file should quote identifiers for postgres, want %s\n%s", want, content)
+	}
+}
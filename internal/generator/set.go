@@ -0,0 +1,154 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/ttaatoo/sqlgen/internal/schema"
+)
+
+// Set columns get a bit set type, such as UsersPerms for the perms column of
+// users, with a constant per member:
+//
+//	func (u UsersPerms) Has(flags UsersPerms) bool
+//	func (u *UsersPerms) Add(flags UsersPerms)
+//	func (u *UsersPerms) Remove(flags UsersPerms)
+//	func (u UsersPerms) Members() []string
+//	func (u UsersPerms) String() string
+//	func (u *UsersPerms) Scan(src any) error
+//	func (u UsersPerms) Value() (driver.Value, error)
+//
+// MySQL allows at most 64 members, so the type is a uint64. Scan and Value
+// read and write the comma-separated members MySQL uses. A type override of
+// the column, such as set: string, turns this off. As for enums, only the
+// receiver has a one-letter name.
+
+// setType returns the name of the Go type generated for col of table, or ""
+// if col is not a set column or its type is overridden.
+func (g *Generator) setType(table *schema.Table, col schema.Column) string {
+	if len(col.SetMembers) == 0 || len(col.SetMembers) > 64 {
+		return ""
+	}
+	if _, ok := g.override(table, col); ok {
+		return ""
+	}
+	return g.camel(table.Name) + g.camel(col.Name)
+}
+
+// setColumns returns the columns of table that get a set type.
+func (g *Generator) setColumns(table *schema.Table) []schema.Column {
+	var cols []schema.Column
+	for _, col := range table.Columns {
+		if g.setType(table, col) != "" {
+			cols = append(cols, col)
+		}
+	}
+	return cols
+}
+
+// setImports returns the imports used by the set types of table.
+func (g *Generator) setImports(table *schema.Table) []string {
+	if len(g.setColumns(table)) == 0 {
+		return nil
+	}
+	return []string{"database/sql/driver", "fmt", "strings"}
+}
+
+func (g *Generator) generateSets(buf *bytes.Buffer, table *schema.Table) {
+	for _, col := range g.setColumns(table) {
+		g.generateSet(buf, table, col)
+	}
+}
+
+func (g *Generator) generateSet(buf *bytes.Buffer, table *schema.Table, col schema.Column) {
+	typeName := g.setType(table, col)
+	recv := receiverName(typeName)
+	names := g.constNames(typeName, col.SetMembers)
+	first, size := utf8.DecodeRuneInString(typeName)
+	membersVar := string(unicode.ToLower(first)) + typeName[size:] + "Members"
+
+	quoted := make([]string, len(col.SetMembers))
+	for i, member := range col.SetMembers {
+		quoted[i] = strconv.Quote(member)
+	}
+
+	buf.WriteString(fmt.Sprintf("\n// %s is a set of members of the set column %s of %s.\n", typeName, col.Name, table.Name))
+	buf.WriteString(fmt.Sprintf("type %s uint64\n", typeName))
+
+	buf.WriteString(fmt.Sprintf("\n// Members of %s.\nconst (\n", typeName))
+	for i, name := range names {
+		if i == 0 {
+			buf.WriteString(fmt.Sprintf("\t%s %s = 1 << iota\n", name, typeName))
+		} else {
+			buf.WriteString(fmt.Sprintf("\t%s\n", name))
+		}
+	}
+	buf.WriteString(")\n")
+
+	buf.WriteString(fmt.Sprintf("\n// %s holds the names of the members of %s in bit order.\n", membersVar, typeName))
+	buf.WriteString(fmt.Sprintf("var %s = [...]string{%s}\n", membersVar, strings.Join(quoted, ", ")))
+
+	buf.WriteString(fmt.Sprintf("\n// Has reports whether %s contains every member of flags.\n", recv))
+	buf.WriteString(fmt.Sprintf("func (%s %s) Has(flags %s) bool {\n", recv, typeName, typeName))
+	buf.WriteString(fmt.Sprintf("\treturn %s&flags == flags\n", recv))
+	buf.WriteString("}\n")
+
+	buf.WriteString(fmt.Sprintf("\n// Add adds the members of flags to %s.\n", recv))
+	buf.WriteString(fmt.Sprintf("func (%s *%s) Add(flags %s) {\n", recv, typeName, typeName))
+	buf.WriteString(fmt.Sprintf("\t*%s |= flags\n", recv))
+	buf.WriteString("}\n")
+
+	buf.WriteString(fmt.Sprintf("\n// Remove removes the members of flags from %s.\n", recv))
+	buf.WriteString(fmt.Sprintf("func (%s *%s) Remove(flags %s) {\n", recv, typeName, typeName))
+	buf.WriteString(fmt.Sprintf("\t*%s &^= flags\n", recv))
+	buf.WriteString("}\n")
+
+	buf.WriteString(fmt.Sprintf("\n// Members returns the names of the members of %s in declaration order.\n", recv))
+	buf.WriteString(fmt.Sprintf("func (%s %s) Members() []string {\n", recv, typeName))
+	buf.WriteString("\tvar names []string\n")
+	buf.WriteString(fmt.Sprintf("\tfor bit, name := range %s {\n", membersVar))
+	buf.WriteString(fmt.Sprintf("\t\tif %s&(1<<bit) != 0 {\n\t\t\tnames = append(names, name)\n\t\t}\n", recv))
+	buf.WriteString("\t}\n")
+	buf.WriteString("\treturn names\n")
+	buf.WriteString("}\n")
+
+	buf.WriteString(fmt.Sprintf("\n// String returns the members of %s separated by commas, as MySQL writes\n// them.\n", recv))
+	buf.WriteString(fmt.Sprintf("func (%s %s) String() string {\n", recv, typeName))
+	buf.WriteString(fmt.Sprintf("\treturn strings.Join(%s.Members(), \",\")\n", recv))
+	buf.WriteString("}\n")
+
+	buf.WriteString("\n// Scan implements sql.Scanner, reading the comma-separated members of the\n// set.\n")
+	buf.WriteString(fmt.Sprintf("func (%s *%s) Scan(src any) error {\n", recv, typeName))
+	buf.WriteString("\tvar text string\n")
+	buf.WriteString("\tswitch value := src.(type) {\n")
+	buf.WriteString("\tcase string:\n\t\ttext = value\n")
+	buf.WriteString("\tcase []byte:\n\t\ttext = string(value)\n")
+	buf.WriteString(fmt.Sprintf("\tdefault:\n\t\treturn fmt.Errorf(\"cannot scan %%T into %s\", src)\n", typeName))
+	buf.WriteString("\t}\n")
+	buf.WriteString(fmt.Sprintf("\tvar set %s\n", typeName))
+	buf.WriteString("\tif text != \"\" {\n")
+	buf.WriteString("\t\tfor _, name := range strings.Split(text, \",\") {\n")
+	buf.WriteString("\t\t\tswitch name {\n")
+	for i, name := range names {
+		buf.WriteString(fmt.Sprintf("\t\t\tcase %s:\n\t\t\t\tset |= %s\n", quoted[i], name))
+	}
+	buf.WriteString(fmt.Sprintf("\t\t\tdefault:\n\t\t\t\treturn fmt.Errorf(\"unknown %s member %%q\", name)\n", typeName))
+	buf.WriteString("\t\t\t}\n")
+	buf.WriteString("\t\t}\n")
+	buf.WriteString("\t}\n")
+	buf.WriteString(fmt.Sprintf("\t*%s = set\n", recv))
+	buf.WriteString("\treturn nil\n")
+	buf.WriteString("}\n")
+
+	buf.WriteString(fmt.Sprintf("\n// Value implements driver.Valuer. It refuses bits that are not members of\n// %s.\n", typeName))
+	buf.WriteString(fmt.Sprintf("func (%s %s) Value() (driver.Value, error) {\n", recv, typeName))
+	buf.WriteString(fmt.Sprintf("\tif %s&^(1<<%d-1) != 0 {\n", recv, len(names)))
+	buf.WriteString(fmt.Sprintf("\t\treturn nil, fmt.Errorf(\"invalid %s value %%#x\", uint64(%s))\n", typeName, recv))
+	buf.WriteString("\t}\n")
+	buf.WriteString(fmt.Sprintf("\treturn %s.String(), nil\n", recv))
+	buf.WriteString("}\n")
+}
@@ -0,0 +1,129 @@
+package generator
+
+import (
+	"go/format"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/ttaatoo/sqlgen/internal/schema"
+)
+
+func TestGenerateSet(t *testing.T) {
+	gen := New("models", "/tmp/output")
+
+	src, err := format.Source([]byte(mustRender(t, gen, testTable("set"))))
+	if err != nil {
+		t.Fatalf("generated code does not parse: %v", err)
+	}
+	code := string(src)
+	for _, want := range []string{
+		"\"database/sql/driver\"\n\t\"fmt\"\n\t\"strings\"\n",
+		"Perms  MembersPerms  `db:\"perms\"`",
+		"Tags   *MembersTags  `db:\"tags\"`",
+		"// MembersPerms is a set of members of the set column perms of members.\ntype MembersPerms uint64\n",
+		"MembersPermsRead MembersPerms = 1 << iota\n\tMembersPermsWrite\n\tMembersPermsAdmin\n",
+		"var membersPermsMembers = [...]string{\"read\", \"write\", \"Admin\"}",
+		"func (m MembersPerms) Has(flags MembersPerms) bool {\n\treturn m&flags == flags\n}",
+		"func (m *MembersPerms) Add(flags MembersPerms) {",
+		"func (m *MembersPerms) Remove(flags MembersPerms) {",
+		"func (m MembersPerms) Members() []string {",
+		"func (m MembersPerms) String() string {",
+		"func (m *MembersPerms) Scan(src any) error {",
+		"case \"Admin\":\n\t\t\t\tset |= MembersPermsAdmin\n",
+		"func (m MembersPerms) Value() (driver.Value, error) {\n\tif m&^(1<<3-1) != 0 {",
+		"type MembersTags uint64",
+		"type MembersStatus string",
+	} {
+		if !strings.Contains(code, want) {
+			t.Errorf("generated code should contain %q\n%s", want, code)
+		}
+	}
+	if n := strings.Count(code, "\"database/sql/driver\""); n != 1 {
+		t.Errorf("driver should be imported once, got %d\n%s", n, code)
+	}
+}
+
+func TestGenerateSetOverridden(t *testing.T) {
+	gen := New("models", "/tmp/output", WithTypeOverrides(map[string]TypeOverride{"set": {Type: "string"}}))
+
+	code := mustRender(t, gen, testTable("set"))
+	for _, unwanted := range []string{"MembersPerms", "MembersTags", "strings"} {
+		if strings.Contains(code, unwanted) {
+			t.Errorf("overridden set columns should not contain %q\n%s", unwanted, code)
+		}
+	}
+	if !strings.Contains(code, "MembersStatus") {
+		t.Errorf("overriding sets should keep the enum type\n%s", code)
+	}
+}
+
+func TestGenerateSetClash(t *testing.T) {
+	gen := New("models", "/tmp/output", WithDryRun(io.Discard))
+	if err := gen.Generate(testTable("set")); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	permsTable := &schema.Table{Name: "members_perms", Columns: []schema.Column{{Name: "id", DataType: "int"}}}
+	if err := gen.Generate(permsTable); err == nil || !strings.Contains(err.Error(), "type MembersPerms") {
+		t.Errorf("Generate() error = %v, want a clash of MembersPerms", err)
+	}
+}
+
+func TestSetGoType(t *testing.T) {
+	table := testTable("set")
+	tests := []struct {
+		nullable Nullable
+		want     string
+	}{
+		{NullPointer, "*MembersTags"},
+		{NullSQL, "sql.Null[MembersTags]"},
+		{NullGeneric, "sql.Null[MembersTags]"},
+	}
+
+	for _, tt := range tests {
+		gen := New("models", "/tmp/output", WithNullable(tt.nullable))
+		if got := gen.goType(table, table.Columns[2]); got != tt.want {
+			t.Errorf("goType() with %s = %q, want %q", tt.nullable, got, tt.want)
+		}
+	}
+
+	// A set with more members than bits in a uint64 stays a string.
+	gen := New("models", "/tmp/output")
+	col := schema.Column{Name: "big", DataType: "set", SetMembers: make([]string, 65)}
+	if got := gen.goType(table, col); got != "string" {
+		t.Errorf("goType() of 65-member set = %q, want string", got)
+	}
+}
+
+func TestSetScanValue(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "sqlgen_test")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	if err := New("models", tmpDir, WithCRUD(true)).Generate(testTable("set")); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	runGenerated(t, tmpDir, `package models
+
+import "testing"
+
+func TestSet(t *testing.T) {
+	var p MembersPerms
+	if err := p.Scan("read,Admin"); err != nil || p != MembersPermsRead|MembersPermsAdmin {
+		t.Errorf("Scan() = %v, %v, want read and Admin", p, err)
+	}
+	if err := p.Scan("admin"); err == nil {
+		t.Error("Scan() of an unknown member should fail")
+	}
+	if v, err := (MembersPermsRead | MembersPermsAdmin).Value(); v != "read,Admin" || err != nil {
+		t.Errorf("Value() = %#v, %v, want read,Admin", v, err)
+	}
+	if _, err := MembersPerms(1 << 3).Value(); err == nil {
+		t.Error("Value() of a bit that is no member should fail")
+	}
+}
+`)
+}
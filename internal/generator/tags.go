@@ -0,0 +1,218 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/ttaatoo/sqlgen/internal/schema"
+)
+
+// Tag configures a struct tag added to every field next to db:"...".
+//
+// The built-in kinds are:
+//
+//	json, yaml  the column name, with ,omitempty for nullable columns
+//	gorm        column:name;primaryKey;autoIncrement;type:...;not null
+//	validate    required for NOT NULL text and time columns, max=N from
+//	            the declared length of char and varchar columns
+//
+// Any other key gets the column name. A template replaces the built-in value.
+type Tag struct {
+	// Key is the tag key, such as "json".
+	Key string
+	// Case is the style of the column name in name tags such as json:
+	// "snake" keeps the column name (the default) and "camel" converts it
+	// to lowerCamelCase.
+	Case string
+	// OmitEmpty adds ,omitempty to the name tags of nullable columns.
+	OmitEmpty bool
+	// Template, if set, renders the tag value from a TagData. A tag whose
+	// value is empty is left out.
+	Template *template.Template
+}
+
+// DefaultTag returns the built-in settings of the tag kind key.
+func DefaultTag(key string) Tag {
+	tag := Tag{Key: key, Case: "snake"}
+	if key == "json" || key == "yaml" {
+		tag.OmitEmpty = true
+	}
+	return tag
+}
+
+// TagData is the value tag templates are executed with. The fields of the
+// column are promoted, so templates can use .Name and .IsNullable directly.
+type TagData struct {
+	schema.Column
+	Table *schema.Table
+	// Field is the Go name of the column's field.
+	Field string
+	// Type is the Go type of the column's field.
+	Type string
+	// Length is the declared length of a char or varchar column, or 0.
+	Length int
+}
+
+// tagFuncs returns the functions available to tag templates. camel writes
+// the initialisms of g in upper case, like the field names.
+func (g *Generator) tagFuncs() template.FuncMap {
+	return template.FuncMap{
+		"camel":      g.camel,
+		"lowerCamel": toLowerCamelCase,
+		"snake":      toSnakeCase,
+	}
+}
+
+// ParseTagTemplate parses text as the template of a tag value, such as
+// {{.Name}},omitempty. See TagData for the fields available to it.
+func ParseTagTemplate(key, text string) (*template.Template, error) {
+	g := &Generator{}
+	tmpl, err := template.New(key).Funcs(g.tagFuncs()).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s tag template: %w", key, err)
+	}
+	return tmpl, nil
+}
+
+// structTag returns the struct tag of the field for col of table.
+func (g *Generator) structTag(table *schema.Table, col schema.Column) (string, error) {
+	tags := []string{fmt.Sprintf("db:%q", col.Name)}
+	for _, tag := range g.tags {
+		value, err := g.tagValue(table, col, tag)
+		if err != nil {
+			return "", err
+		}
+		switch {
+		case tag.Key == "db":
+			tags[0] = fmt.Sprintf("db:%q", value)
+		case value != "":
+			tags = append(tags, fmt.Sprintf("%s:%q", tag.Key, value))
+		}
+	}
+	return "`" + strings.Join(tags, " ") + "`", nil
+}
+
+func (g *Generator) tagValue(table *schema.Table, col schema.Column, tag Tag) (string, error) {
+	if tag.Template != nil {
+		var buf bytes.Buffer
+		data := TagData{
+			Column: col,
+			Table:  table,
+			Field:  g.camel(col.Name),
+			Type:   g.goType(table, col),
+			Length: columnLength(col),
+		}
+		if err := tag.Template.Funcs(g.tagFuncs()).Execute(&buf, data); err != nil {
+			return "", fmt.Errorf("failed to execute %s tag template: %w", tag.Key, err)
+		}
+		return buf.String(), nil
+	}
+
+	switch tag.Key {
+	case "gorm":
+		return gormTag(col), nil
+	case "validate":
+		typ := g.baseType(table, col)
+		if g.enumType(table, col) != "" {
+			typ = "string"
+		}
+		return validateTag(col, typ), nil
+	}
+	name := col.Name
+	if tag.Case == "camel" {
+		name = toLowerCamelCase(name)
+	}
+	if tag.OmitEmpty && col.IsNullable {
+		name += ",omitempty"
+	}
+	return name, nil
+}
+
+// gormTag returns the gorm tag of col, naming the column and its key, type
+// and nullability so that AutoMigrate recreates the same table.
+func gormTag(col schema.Column) string {
+	parts := []string{"column:" + col.Name}
+	if col.ColumnKey == "PRI" {
+		parts = append(parts, "primaryKey")
+	}
+	if isAutoIncrement(col) {
+		parts = append(parts, "autoIncrement")
+	}
+	if col.ColumnKey == "UNI" {
+		parts = append(parts, "unique")
+	}
+	typ := col.ColumnType
+	if typ == "" {
+		typ = col.DataType
+	}
+	if typ != "" {
+		parts = append(parts, "type:"+typ)
+	}
+	if !col.IsNullable && col.ColumnKey != "PRI" {
+		parts = append(parts, "not null")
+	}
+	return strings.Join(parts, ";")
+}
+
+// validateTag returns the go-playground/validator rules for col, whose
+// non-null Go type is goType. Values the database assigns, and zero values
+// that are valid for numbers and booleans, are not required.
+func validateTag(col schema.Column, goType string) string {
+	var rules []string
+	switch {
+	case col.IsNullable:
+		rules = append(rules, "omitempty")
+	case isAutoIncrement(col), isGeneratedColumn(col):
+	case goType == "string", goType == "[]byte", goType == "time.Time":
+		rules = append(rules, "required")
+	}
+	if n := columnLength(col); n > 0 {
+		rules = append(rules, "max="+strconv.Itoa(n))
+	}
+	if len(rules) == 1 && rules[0] == "omitempty" {
+		return ""
+	}
+	return strings.Join(rules, ",")
+}
+
+// columnLength returns the declared length of a char or varchar column,
+// read from its column type, or 0.
+func columnLength(col schema.Column) int {
+	switch strings.ToLower(col.DataType) {
+	case "char", "varchar", "nchar", "nvarchar", "bpchar", "character", "character varying":
+	default:
+		return 0
+	}
+	_, args, ok := strings.Cut(col.ColumnType, "(")
+	if !ok {
+		return 0
+	}
+	args, _, _ = strings.Cut(args, ")")
+	n, err := strconv.Atoi(strings.TrimSpace(args))
+	if err != nil {
+		return 0
+	}
+	return n
+}
+
+// toLowerCamelCase converts a snake_case name to lowerCamelCase without
+// initialisms, as JSON field names are usually written: user_id becomes
+// userId.
+func toLowerCamelCase(s string) string {
+	parts := strings.Split(s, "_")
+	var result strings.Builder
+	for _, part := range parts {
+		if part == "" {
+			continue
+		}
+		if result.Len() == 0 {
+			result.WriteString(strings.ToLower(part[:1]) + part[1:])
+		} else {
+			result.WriteString(strings.ToUpper(part[:1]) + part[1:])
+		}
+	}
+	return result.String()
+}
@@ -0,0 +1,177 @@
+package generator
+
+import (
+	"testing"
+
+	"github.com/ttaatoo/sqlgen/internal/schema"
+)
+
+func TestStructTag(t *testing.T) {
+	tmpl, err := ParseTagTemplate("xml", `{{if .IsNullable}}{{lowerCamel .Name}},omitempty{{end}}`)
+	if err != nil {
+		t.Fatalf("ParseTagTemplate() error = %v", err)
+	}
+	camel := DefaultTag("json")
+	camel.Case = "camel"
+
+	tests := []struct {
+		name string
+		tags []Tag
+		want []string
+	}{
+		{
+			name: "db only",
+			want: []string{
+				"`db:\"id\"`",
+				"`db:\"email\"`",
+				"`db:\"display_name\"`",
+				"`db:\"age\"`",
+				"`db:\"bio\"`",
+				"`db:\"created_at\"`",
+			},
+		},
+		{
+			name: "json and yaml",
+			tags: []Tag{DefaultTag("json"), DefaultTag("yaml")},
+			want: []string{
+				"`db:\"id\" json:\"id\" yaml:\"id\"`",
+				"`db:\"email\" json:\"email\" yaml:\"email\"`",
+				"`db:\"display_name\" json:\"display_name,omitempty\" yaml:\"display_name,omitempty\"`",
+				"`db:\"age\" json:\"age\" yaml:\"age\"`",
+				"`db:\"bio\" json:\"bio,omitempty\" yaml:\"bio,omitempty\"`",
+				"`db:\"created_at\" json:\"created_at\" yaml:\"created_at\"`",
+			},
+		},
+		{
+			name: "camel json",
+			tags: []Tag{camel},
+			want: []string{
+				"`db:\"id\" json:\"id\"`",
+				"`db:\"email\" json:\"email\"`",
+				"`db:\"display_name\" json:\"displayName,omitempty\"`",
+				"`db:\"age\" json:\"age\"`",
+				"`db:\"bio\" json:\"bio,omitempty\"`",
+				"`db:\"created_at\" json:\"createdAt\"`",
+			},
+		},
+		{
+			name: "gorm",
+			tags: []Tag{DefaultTag("gorm")},
+			want: []string{
+				"`db:\"id\" gorm:\"column:id;primaryKey;autoIncrement;type:bigint unsigned\"`",
+				"`db:\"email\" gorm:\"column:email;unique;type:varchar(255);not null\"`",
+				"`db:\"display_name\" gorm:\"column:display_name;type:varchar(100)\"`",
+				"`db:\"age\" gorm:\"column:age;type:int;not null\"`",
+				"`db:\"bio\" gorm:\"column:bio;type:text\"`",
+				"`db:\"created_at\" gorm:\"column:created_at;type:datetime;not null\"`",
+			},
+		},
+		{
+			name: "validate",
+			tags: []Tag{DefaultTag("validate")},
+			want: []string{
+				"`db:\"id\"`",
+				"`db:\"email\" validate:\"required,max=255\"`",
+				"`db:\"display_name\" validate:\"omitempty,max=100\"`",
+				"`db:\"age\"`",
+				"`db:\"bio\"`",
+				"`db:\"created_at\" validate:\"required\"`",
+			},
+		},
+		{
+			name: "template",
+			tags: []Tag{{Key: "xml", Template: tmpl}},
+			want: []string{
+				"`db:\"id\"`",
+				"`db:\"email\"`",
+				"`db:\"display_name\" xml:\"displayName,omitempty\"`",
+				"`db:\"age\"`",
+				"`db:\"bio\" xml:\"bio,omitempty\"`",
+				"`db:\"created_at\"`",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gen := New("models", "/tmp/output", WithTags(tt.tags...))
+			table := testTable("tags")
+			for i, col := range table.Columns {
+				got, err := gen.structTag(table, col)
+				if err != nil {
+					t.Fatalf("structTag(%s) error = %v", col.Name, err)
+				}
+				if got != tt.want[i] {
+					t.Errorf("structTag(%s) = %s, want %s", col.Name, got, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestStructTagCamelInitialisms(t *testing.T) {
+	tmpl, err := ParseTagTemplate("field", `{{camel .Name}}`)
+	if err != nil {
+		t.Fatalf("ParseTagTemplate() error = %v", err)
+	}
+
+	// camel names the column like its field, with the configured initialisms.
+	gen := New("models", "/tmp/output", WithInitialisms("sku"), WithTags(Tag{Key: "field", Template: tmpl}))
+	table := &schema.Table{Name: "items", Columns: []schema.Column{{Name: "sku_id", DataType: "int"}}}
+	got, err := gen.structTag(table, table.Columns[0])
+	if err != nil {
+		t.Fatalf("structTag() error = %v", err)
+	}
+	if want := "`db:\"sku_id\" field:\"SKUID\"`"; got != want {
+		t.Errorf("structTag() = %s, want %s", got, want)
+	}
+}
+
+func TestParseTagTemplateError(t *testing.T) {
+	if _, err := ParseTagTemplate("json", "{{.Name"); err == nil {
+		t.Error("ParseTagTemplate() should fail on an unterminated action")
+	}
+}
+
+func TestColumnLength(t *testing.T) {
+	tests := []struct {
+		col  schema.Column
+		want int
+	}{
+		{schema.Column{DataType: "varchar", ColumnType: "varchar(255)"}, 255},
+		{schema.Column{DataType: "char", ColumnType: "char(36)"}, 36},
+		{schema.Column{DataType: "bpchar", ColumnType: "character(2)"}, 2},
+		{schema.Column{DataType: "varchar", ColumnType: "character varying(80)"}, 80},
+		{schema.Column{DataType: "varchar"}, 0},
+		{schema.Column{DataType: "decimal", ColumnType: "decimal(10,2)"}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.col.ColumnType, func(t *testing.T) {
+			if got := columnLength(tt.col); got != tt.want {
+				t.Errorf("columnLength() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestToLowerCamelCase(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"user_id", "userId"},
+		{"created_at", "createdAt"},
+		{"Name", "name"},
+		{"_private", "private"},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if got := toLowerCamelCase(tt.input); got != tt.want {
+				t.Errorf("toLowerCamelCase(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,135 @@
+package generator
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"text/template"
+
+	"github.com/ttaatoo/sqlgen/internal/schema"
+)
+
+//go:embed templates/*.tmpl
+var builtinTemplates embed.FS
+
+// entryTemplate is the template rendered for each table unless a single
+// template file is supplied.
+const entryTemplate = "table.tmpl"
+
+// TemplateData is the value templates are executed with. The fields of the
+// table are promoted, so templates can use .Name and .Columns directly.
+type TemplateData struct {
+	*schema.Table
+	Package string
+}
+
+// funcMap returns the functions available to templates rendering table:
+//
+//	camel      user_id -> UserID
+//	snake      UsersId -> users_id
+//	goType     the Go type of a column
+//	tag        the struct tag of a column's field, with backquotes
+//	comment    a table or column comment as // lines, or "" when empty
+//	indent     text with every line indented by a tab
+//	imports    the import paths the generated file needs
+//	enums      the types of the table's enum columns
+//	sets       the types of the table's set columns
+//	crud       the -crud functions, or "" when disabled
+//	relations  the -relations loaders, or "" when disabled
+func (g *Generator) funcMap(table *schema.Table) template.FuncMap {
+	return template.FuncMap{
+		"camel":   g.camel,
+		"snake":   toSnakeCase,
+		"comment": docComment,
+		"indent":  indent,
+		"goType": func(col schema.Column) string {
+			return g.goType(table, col)
+		},
+		"tag": func(col schema.Column) (string, error) {
+			return g.structTag(table, col)
+		},
+		"imports": func() []string {
+			return g.collectImports(table)
+		},
+		"enums": func() string {
+			var buf bytes.Buffer
+			g.generateEnums(&buf, table)
+			return buf.String()
+		},
+		"sets": func() string {
+			var buf bytes.Buffer
+			g.generateSets(&buf, table)
+			return buf.String()
+		},
+		"crud": func() string {
+			if !g.crud {
+				return ""
+			}
+			var buf bytes.Buffer
+			g.generateCRUD(&buf, table)
+			return buf.String()
+		},
+		"relations": func() string {
+			if !g.relations {
+				return ""
+			}
+			var buf bytes.Buffer
+			g.generateRelations(&buf, table)
+			return buf.String()
+		},
+	}
+}
+
+// ParseTemplates returns the template rendered for each table. The built-in
+// templates are parsed first, then the user templates at path, if any.
+//
+// A file replaces the built-in table template. A directory contributes every
+// .tmpl file in it; one named table.tmpl replaces the built-in entry point and
+// the others may redefine built-in blocks such as "struct".
+func ParseTemplates(path string) (*template.Template, error) {
+	g := &Generator{}
+	tmpl, err := template.New(entryTemplate).Funcs(g.funcMap(&schema.Table{})).
+		ParseFS(builtinTemplates, "templates/*.tmpl")
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse built-in templates: %w", err)
+	}
+	if path == "" {
+		return tmpl, nil
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read templates: %w", err)
+	}
+
+	entry := entryTemplate
+	files := []string{path}
+	if info.IsDir() {
+		files, err = filepath.Glob(filepath.Join(path, "*.tmpl"))
+		if err != nil {
+			return nil, fmt.Errorf("failed to list templates: %w", err)
+		}
+		if len(files) == 0 {
+			return nil, fmt.Errorf("no .tmpl files in %s", path)
+		}
+	} else {
+		entry = filepath.Base(path)
+	}
+
+	if _, err := tmpl.ParseFiles(files...); err != nil {
+		return nil, fmt.Errorf("failed to parse templates: %w", err)
+	}
+	return tmpl.Lookup(entry), nil
+}
+
+// render executes the generator's template for table.
+func (g *Generator) render(table *schema.Table) (string, error) {
+	var buf bytes.Buffer
+	data := TemplateData{Table: table, Package: g.packageName}
+	if err := g.template.Funcs(g.funcMap(table)).Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("failed to execute template: %w", err)
+	}
+	return buf.String(), nil
+}
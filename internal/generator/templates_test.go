@@ -0,0 +1,141 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseTemplatesFile(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "sqlgen_test")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	writeFiles(t, tmpDir, map[string]string{
+		"model.tmpl": `package {{.Package}}
+{{range imports}}// import {{.}}
+{{end}}
+// {{camel .Name}} maps {{snake (camel .Name)}}.
+{{template "struct" .}}
+func ({{camel .Name}}) TableName() string { return {{printf "%q" .Name}} }
+{{range .Columns}}// {{.Name}}: {{goType .}}
+{{end}}`,
+	})
+
+	tmpl, err := ParseTemplates(filepath.Join(tmpDir, "model.tmpl"))
+	if err != nil {
+		t.Fatalf("ParseTemplates() error = %v", err)
+	}
+	gen := New("models", tmpDir, WithTemplate(tmpl))
+	code := mustRender(t, gen, testTable("template"))
+
+	for _, want := range []string{
+		"package models",
+		"// import time",
+		"// UserAccounts maps user_accounts.",
+		"type UserAccounts struct",
+		"\tCreatedAt time.Time `db:\"created_at\"`",
+		`func (UserAccounts) TableName() string { return "user_accounts" }`,
+		"// id: int64",
+	} {
+		if !strings.Contains(code, want) {
+			t.Errorf("rendered code should contain %q\n%s", want, code)
+		}
+	}
+}
+
+func TestParseTemplatesDirectory(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "sqlgen_test")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	writeFiles(t, tmpDir, map[string]string{
+		"struct.tmpl": `{{define "struct"}}type {{camel .Name}} struct {
+{{- range .Columns}}
+	{{camel .Name}} {{goType .}} ` + "`json:\"{{.Name}}\"`" + `
+{{- end}}
+}
+{{end}}`,
+		"notes.txt": "ignored",
+	})
+
+	tmpl, err := ParseTemplates(tmpDir)
+	if err != nil {
+		t.Fatalf("ParseTemplates() error = %v", err)
+	}
+	gen := New("models", tmpDir, WithTemplate(tmpl), WithCRUD(true))
+	code := mustRender(t, gen, testTable("template"))
+
+	for _, want := range []string{
+		"package models",
+		`"context"`,
+		"\tID int64 `json:\"id\"`",
+		"func (u *UserAccounts) Insert(ctx context.Context, db DBTX) error {",
+	} {
+		if !strings.Contains(code, want) {
+			t.Errorf("rendered code should contain %q\n%s", want, code)
+		}
+	}
+	if strings.Contains(code, "`db:") {
+		t.Errorf("struct block should be overridden\n%s", code)
+	}
+}
+
+func TestParseTemplatesErrors(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "sqlgen_test")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	emptyDir := filepath.Join(tmpDir, "empty")
+	if err := os.Mkdir(emptyDir, 0755); err != nil {
+		t.Fatalf("failed to create dir: %v", err)
+	}
+	writeFiles(t, tmpDir, map[string]string{
+		"bad.tmpl":     "{{if}}",
+		"unknown.tmpl": "{{pluralize .Name}}",
+	})
+
+	tests := []struct {
+		name string
+		path string
+	}{
+		{"missing path", filepath.Join(tmpDir, "missing.tmpl")},
+		{"empty directory", emptyDir},
+		{"syntax error", filepath.Join(tmpDir, "bad.tmpl")},
+		{"unknown function", filepath.Join(tmpDir, "unknown.tmpl")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseTemplates(tt.path); err == nil {
+				t.Error("ParseTemplates() error = nil, want error")
+			}
+		})
+	}
+}
+
+func TestGenerateTemplateExecutionError(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "sqlgen_test")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	writeFiles(t, tmpDir, map[string]string{"bad.tmpl": "{{template \"missing\" .}}"})
+	tmpl, err := ParseTemplates(filepath.Join(tmpDir, "bad.tmpl"))
+	if err != nil {
+		t.Fatalf("ParseTemplates() error = %v", err)
+	}
+
+	gen := New("models", filepath.Join(tmpDir, "out"), WithTemplate(tmpl))
+	if err := gen.Generate(testTable("template")); err == nil {
+		t.Error("Generate() error = nil, want error")
+	}
+}
@@ -0,0 +1,476 @@
+package generator
+
+import (
+	"fmt"
+	"go/token"
+	"path"
+	"strings"
+
+	"github.com/ttaatoo/sqlgen/internal/schema"
+)
+
+// packageImports maps the package qualifiers used by generated field types
+// to their import paths.
+var packageImports = map[string]string{
+	"time": "time",
+	"json": "encoding/json",
+	"pq":   "github.com/lib/pq",
+	"sql":  "database/sql",
+}
+
+// importPaths returns the import paths needed by goType, which may name
+// several packages, as in sql.Null[time.Time].
+func importPaths(goType string) []string {
+	var paths []string
+	for _, f := range strings.FieldsFunc(goType, func(r rune) bool {
+		return r == '*' || r == '[' || r == ']' || r == ',' || r == ' '
+	}) {
+		pkg, _, ok := strings.Cut(f, ".")
+		if path, known := packageImports[pkg]; ok && known {
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
+
+// goType returns the Go type of col, mapped according to the table's
+// dialect. Tables without a dialect are treated as MySQL.
+func goType(dialect schema.Dialect, col schema.Column) string {
+	switch dialect {
+	case schema.Postgres:
+		return postgresTypeToGo(col.DataType, col.IsNullable, col.IsArray)
+	case schema.SQLite:
+		return sqliteTypeToGo(col.DataType, col.IsNullable)
+	default:
+		return mysqlTypeToGo(col.DataType, col.IsNullable, col.IsUnsigned)
+	}
+}
+
+// TypeOverride replaces the built-in mapping of a column's type.
+type TypeOverride struct {
+	// Type is the Go type of non-null columns, such as "decimal.Decimal".
+	Type string
+	// Import is the import path of Type's package. It may be omitted for
+	// the packages the built-in mapping already uses.
+	Import string
+	// Nullable is the Go type of nullable columns. It defaults to a pointer
+	// to Type.
+	Nullable string
+	// JSON marks Type as the Go value the column holds as JSON, such as a
+	// struct. The field is then a JSON[Type], which marshals and
+	// unmarshals it.
+	JSON bool
+}
+
+// override returns the type override applying to col of table. The most
+// specific one wins: an exact table.column key, then a matching
+// table.column pattern, those of the key a single-column foreign key
+// references, the full column type, the data type and finally the decimal
+// strategy for exact numeric columns and the JSON strategy for JSON columns.
+//
+// A foreign key column thus has the type of the key it references, which
+// relation loaders pass it to, unless it has an override of its own.
+func (g *Generator) override(table *schema.Table, col schema.Column) (TypeOverride, bool) {
+	if o, ok := g.columnOverride(table.Name, col.Name); ok {
+		return o, true
+	}
+	for _, fk := range singleColumn(table.ForeignKeys) {
+		if strings.EqualFold(fk.Columns[0], col.Name) {
+			if o, ok := g.columnOverride(fk.RefTable, fk.RefColumns[0]); ok {
+				return o, true
+			}
+		}
+	}
+	if col.ColumnType != "" {
+		if o, ok := g.types[strings.ToLower(col.ColumnType)]; ok {
+			return o, true
+		}
+	}
+	dataType := strings.ToLower(col.DataType)
+	if o, ok := g.types[dataType]; ok {
+		return o, true
+	}
+	if g.decimal.Type != "" && !col.IsArray && (dataType == "decimal" || dataType == "numeric") {
+		return g.decimal, true
+	}
+	if g.json.Type != "" && !col.IsArray && (dataType == "json" || dataType == "jsonb") {
+		return g.json, true
+	}
+	return TypeOverride{}, false
+}
+
+// columnOverride returns the override whose table.column key or pattern
+// matches column of table.
+func (g *Generator) columnOverride(table, column string) (TypeOverride, bool) {
+	name := strings.ToLower(table + "." + column)
+	if o, ok := g.types[name]; ok {
+		return o, true
+	}
+	for _, pattern := range g.patterns {
+		if ok, _ := path.Match(pattern, name); ok {
+			return g.types[pattern], true
+		}
+	}
+	return TypeOverride{}, false
+}
+
+// goType returns the Go type of a column of table, applying any override
+// before the generated enum and set types, the boolean mapping and the
+// dialect's mapping, and wrapping nullable columns according to the
+// nullable strategy.
+func (g *Generator) goType(table *schema.Table, col schema.Column) string {
+	if o, ok := g.override(table, col); ok {
+		typ := o.Type
+		if o.JSON {
+			typ = jsonType(o.Type)
+		}
+		if !col.IsNullable {
+			return typ
+		}
+		if o.Nullable != "" {
+			return o.Nullable
+		}
+		return g.nullableType(typ)
+	}
+	base := g.enumType(table, col)
+	if base == "" {
+		base = g.setType(table, col)
+	}
+	if base == "" {
+		base = g.boolType(table, col)
+	}
+	if base == "" {
+		nonNull := col
+		nonNull.IsNullable = false
+		base = goType(table.Dialect, nonNull)
+	}
+	if !col.IsNullable {
+		return base
+	}
+	return g.nullableType(base)
+}
+
+// typeImports returns the import paths needed by the Go type of col.
+func (g *Generator) typeImports(table *schema.Table, col schema.Column) []string {
+	paths := importPaths(g.goType(table, col))
+	if o, ok := g.override(table, col); ok && o.Import != "" {
+		paths = append(paths, o.Import)
+	}
+	return paths
+}
+
+// Nullable strategies for the Go types of nullable columns.
+type Nullable string
+
+const (
+	// NullPointer uses a pointer, such as *string. It is the default.
+	NullPointer Nullable = "pointer"
+	// NullSQL uses the database/sql Null types, such as sql.NullString,
+	// and sql.Null[T] for types without one.
+	NullSQL Nullable = "sql"
+	// NullGeneric uses sql.Null[T], such as sql.Null[string].
+	NullGeneric Nullable = "generic"
+)
+
+// ParseNullable returns the nullable strategy named s.
+func ParseNullable(s string) (Nullable, error) {
+	switch n := Nullable(s); n {
+	case "":
+		return NullPointer, nil
+	case NullPointer, NullSQL, NullGeneric:
+		return n, nil
+	}
+	return "", fmt.Errorf("invalid nullable strategy %q, want pointer, sql or generic", s)
+}
+
+// sqlNullTypes maps Go types to their database/sql Null type and the name
+// of its value field.
+var sqlNullTypes = map[string]struct{ typ, field string }{
+	"string":    {"sql.NullString", "String"},
+	"int64":     {"sql.NullInt64", "Int64"},
+	"int32":     {"sql.NullInt32", "Int32"},
+	"int16":     {"sql.NullInt16", "Int16"},
+	"uint8":     {"sql.NullByte", "Byte"},
+	"float64":   {"sql.NullFloat64", "Float64"},
+	"bool":      {"sql.NullBool", "Bool"},
+	"time.Time": {"sql.NullTime", "Time"},
+}
+
+// nullableType returns the type of a nullable column whose non-null type is
+// goType under the generator's nullable strategy. Types that can already
+// hold nil are left alone.
+func (g *Generator) nullableType(goType string) string {
+	if canBeNil(goType) {
+		return goType
+	}
+	switch g.nullable {
+	case NullSQL:
+		if t, ok := sqlNullTypes[goType]; ok {
+			return t.typ
+		}
+		return "sql.Null[" + goType + "]"
+	case NullGeneric:
+		return "sql.Null[" + goType + "]"
+	default:
+		return "*" + goType
+	}
+}
+
+// nullValue returns the expression holding the value of a nullable field of
+// type goType and the condition that is true when it is NULL, or value
+// unchanged and "" for types without a separate NULL state.
+func nullValue(goType, value string) (string, string) {
+	switch {
+	case strings.HasPrefix(goType, "*"):
+		return "*" + value, value + " == nil"
+	case strings.HasPrefix(goType, "sql.Null["):
+		return value + ".V", "!" + value + ".Valid"
+	}
+	for _, t := range sqlNullTypes {
+		if t.typ == goType {
+			return value + "." + t.field, "!" + value + ".Valid"
+		}
+	}
+	return value, ""
+}
+
+// validValue returns an expression of the nullable type goType holding the
+// non-null expr, whose type is base.
+func validValue(goType, base, expr string) string {
+	switch {
+	case strings.HasPrefix(goType, "*"):
+		return "&" + expr
+	case strings.HasPrefix(goType, "sql.Null["):
+		return fmt.Sprintf("%s{V: %s, Valid: true}", goType, expr)
+	}
+	if t, ok := sqlNullTypes[base]; ok && t.typ == goType {
+		return fmt.Sprintf("%s{%s: %s, Valid: true}", goType, t.field, expr)
+	}
+	return expr
+}
+
+// ParseDecimal returns the Go type used for DECIMAL and NUMERIC columns by a
+// decimal strategy:
+//
+//	float64      float64, which may lose precision (the default)
+//	string       string, holding the exact value as text
+//	shopspring   decimal.Decimal from github.com/shopspring/decimal
+//	path.Type    a custom type, such as github.com/cockroachdb/apd/v3.Decimal
+//	Type         a custom type declared in the generated package
+func ParseDecimal(strategy string) (TypeOverride, error) {
+	switch strategy {
+	case "", "float64":
+		return TypeOverride{}, nil
+	case "string":
+		return TypeOverride{Type: "string"}, nil
+	case "shopspring":
+		return TypeOverride{
+			Type:     "decimal.Decimal",
+			Import:   "github.com/shopspring/decimal",
+			Nullable: "decimal.NullDecimal",
+		}, nil
+	}
+
+	i := strings.LastIndex(strategy, ".")
+	if i < 0 {
+		if !token.IsIdentifier(strategy) {
+			return TypeOverride{}, fmt.Errorf("invalid decimal type %q", strategy)
+		}
+		return TypeOverride{Type: strategy}, nil
+	}
+	pkgPath, name := strategy[:i], strategy[i+1:]
+	pkg := path.Base(pkgPath)
+	if isMajorVersion(pkg) && path.Dir(pkgPath) != "." {
+		pkg = path.Base(path.Dir(pkgPath))
+	}
+	pkg = strings.ReplaceAll(pkg, "-", "")
+	if !token.IsIdentifier(name) || !token.IsIdentifier(pkg) {
+		return TypeOverride{}, fmt.Errorf("invalid decimal type %q, want import/path.Type", strategy)
+	}
+	return TypeOverride{Type: pkg + "." + name, Import: pkgPath}, nil
+}
+
+// isMajorVersion reports whether elem is a module major version suffix
+// such as v2, which is not part of the package name.
+func isMajorVersion(elem string) bool {
+	if len(elem) < 2 || elem[0] != 'v' {
+		return false
+	}
+	for _, r := range elem[1:] {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// nullableType returns the type of a nullable column whose non-null type is
+// goType. Types that can already hold nil are left alone.
+func nullableType(goType string) string {
+	if canBeNil(goType) {
+		return goType
+	}
+	return "*" + goType
+}
+
+// canBeNil reports whether goType scans NULL as nil without wrapping.
+func canBeNil(goType string) bool {
+	return strings.HasPrefix(goType, "[]") || strings.HasPrefix(goType, "pq.") ||
+		goType == "json.RawMessage" || goType == rawJSONType || goType == "any"
+}
+
+func mysqlTypeToGo(dataType string, nullable bool, unsigned bool) string {
+	var goType string
+
+	switch dataType {
+	case "tinyint":
+		if unsigned {
+			goType = "uint8"
+		} else {
+			goType = "int8"
+		}
+	case "smallint":
+		if unsigned {
+			goType = "uint16"
+		} else {
+			goType = "int16"
+		}
+	case "mediumint", "int", "integer":
+		if unsigned {
+			goType = "uint32"
+		} else {
+			goType = "int32"
+		}
+	case "bigint":
+		if unsigned {
+			goType = "uint64"
+		} else {
+			goType = "int64"
+		}
+	case "float":
+		goType = "float32"
+	case "double", "real":
+		goType = "float64"
+	case "decimal", "numeric":
+		goType = "float64"
+	case "char", "varchar", "text", "tinytext", "mediumtext", "longtext", "enum", "set":
+		goType = "string"
+	case "binary", "varbinary", "blob", "tinyblob", "mediumblob", "longblob":
+		goType = "[]byte"
+	case "datetime", "timestamp", "date", "time":
+		goType = "time.Time"
+	case "year":
+		if unsigned {
+			goType = "uint16"
+		} else {
+			goType = "int16"
+		}
+	case "bit":
+		goType = "[]byte"
+	case "json":
+		goType = "string"
+	default:
+		goType = "string"
+	}
+
+	if nullable {
+		return nullableType(goType)
+	}
+
+	return goType
+}
+
+// postgresTypeToGo maps a Postgres udt name, as read by
+// schema.PostgresReader, to a Go type. Arrays map to the lib/pq array
+// types, which scan NULL as a nil slice.
+func postgresTypeToGo(dataType string, nullable bool, array bool) string {
+	if array {
+		switch dataType {
+		case "int2", "int4":
+			return "pq.Int32Array"
+		case "int8":
+			return "pq.Int64Array"
+		case "float4":
+			return "pq.Float32Array"
+		case "float8", "numeric":
+			return "pq.Float64Array"
+		case "bool":
+			return "pq.BoolArray"
+		case "bytea":
+			return "pq.ByteaArray"
+		default:
+			return "pq.StringArray"
+		}
+	}
+
+	var goType string
+
+	switch dataType {
+	case "int2":
+		goType = "int16"
+	case "int4", "oid":
+		goType = "int32"
+	case "int8":
+		goType = "int64"
+	case "float4":
+		goType = "float32"
+	case "float8":
+		goType = "float64"
+	case "numeric":
+		goType = "float64"
+	case "bool":
+		goType = "bool"
+	case "bytea":
+		goType = "[]byte"
+	case "json", "jsonb":
+		goType = "json.RawMessage"
+	case "date", "time", "timetz", "timestamp", "timestamptz":
+		goType = "time.Time"
+	default:
+		// text, varchar, bpchar, uuid, enum, inet, interval, money and
+		// other types without a closer Go equivalent.
+		goType = "string"
+	}
+
+	if nullable {
+		return nullableType(goType)
+	}
+
+	return goType
+}
+
+// sqliteTypeToGo maps a declared SQLite column type to a Go type. Types the
+// go-sqlite3 driver converts, such as BOOLEAN and DATETIME, are matched by
+// name; anything else follows SQLite's type affinity rules.
+func sqliteTypeToGo(dataType string, nullable bool) string {
+	var goType string
+
+	switch {
+	case dataType == "boolean" || dataType == "bool":
+		goType = "bool"
+	case dataType == "date" || dataType == "datetime" || dataType == "timestamp":
+		goType = "time.Time"
+	case strings.Contains(dataType, "int"):
+		goType = "int64"
+	case strings.Contains(dataType, "char") || strings.Contains(dataType, "clob") ||
+		strings.Contains(dataType, "text"):
+		goType = "string"
+	case strings.Contains(dataType, "blob"):
+		goType = "[]byte"
+	case dataType == "":
+		// No declared type: the column stores values of any type.
+		goType = "any"
+	case strings.Contains(dataType, "real") || strings.Contains(dataType, "floa") ||
+		strings.Contains(dataType, "doub"):
+		goType = "float64"
+	default:
+		// NUMERIC affinity, such as DECIMAL and NUMERIC.
+		goType = "float64"
+	}
+
+	if nullable {
+		return nullableType(goType)
+	}
+
+	return goType
+}
@@ -0,0 +1,420 @@
+package generator
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ttaatoo/sqlgen/internal/schema"
+)
+
+func TestMysqlTypeToGo(t *testing.T) {
+	tests := []struct {
+		name     string
+		dataType string
+		nullable bool
+		unsigned bool
+		want     string
+	}{
+		// Integer types (signed)
+		{"tinyint", "tinyint", false, false, "int8"},
+		{"tinyint nullable", "tinyint", true, false, "*int8"},
+		{"smallint", "smallint", false, false, "int16"},
+		{"smallint nullable", "smallint", true, false, "*int16"},
+		{"mediumint", "mediumint", false, false, "int32"},
+		{"int", "int", false, false, "int32"},
+		{"integer", "integer", false, false, "int32"},
+		{"int nullable", "int", true, false, "*int32"},
+		{"bigint", "bigint", false, false, "int64"},
+		{"bigint nullable", "bigint", true, false, "*int64"},
+
+		// Integer types (unsigned)
+		{"tinyint unsigned", "tinyint", false, true, "uint8"},
+		{"tinyint unsigned nullable", "tinyint", true, true, "*uint8"},
+		{"smallint unsigned", "smallint", false, true, "uint16"},
+		{"smallint unsigned nullable", "smallint", true, true, "*uint16"},
+		{"mediumint unsigned", "mediumint", false, true, "uint32"},
+		{"int unsigned", "int", false, true, "uint32"},
+		{"int unsigned nullable", "int", true, true, "*uint32"},
+		{"bigint unsigned", "bigint", false, true, "uint64"},
+		{"bigint unsigned nullable", "bigint", true, true, "*uint64"},
+
+		// Float types
+		{"float", "float", false, false, "float32"},
+		{"float nullable", "float", true, false, "*float32"},
+		{"double", "double", false, false, "float64"},
+		{"double nullable", "double", true, false, "*float64"},
+		{"real", "real", false, false, "float64"},
+		{"decimal", "decimal", false, false, "float64"},
+		{"numeric", "numeric", false, false, "float64"},
+
+		// String types
+		{"char", "char", false, false, "string"},
+		{"char nullable", "char", true, false, "*string"},
+		{"varchar", "varchar", false, false, "string"},
+		{"varchar nullable", "varchar", true, false, "*string"},
+		{"text", "text", false, false, "string"},
+		{"tinytext", "tinytext", false, false, "string"},
+		{"mediumtext", "mediumtext", false, false, "string"},
+		{"longtext", "longtext", false, false, "string"},
+		{"enum", "enum", false, false, "string"},
+		{"set", "set", false, false, "string"},
+		{"json", "json", false, false, "string"},
+
+		// Binary types
+		{"binary", "binary", false, false, "[]byte"},
+		{"binary nullable", "binary", true, false, "[]byte"},
+		{"varbinary", "varbinary", false, false, "[]byte"},
+		{"blob", "blob", false, false, "[]byte"},
+		{"tinyblob", "tinyblob", false, false, "[]byte"},
+		{"mediumblob", "mediumblob", false, false, "[]byte"},
+		{"longblob", "longblob", false, false, "[]byte"},
+		{"bit", "bit", false, false, "[]byte"},
+
+		// Time types
+		{"datetime", "datetime", false, false, "time.Time"},
+		{"datetime nullable", "datetime", true, false, "*time.Time"},
+		{"timestamp", "timestamp", false, false, "time.Time"},
+		{"timestamp nullable", "timestamp", true, false, "*time.Time"},
+		{"date", "date", false, false, "time.Time"},
+		{"time", "time", false, false, "time.Time"},
+		{"year", "year", false, false, "int16"},
+		{"year unsigned", "year", false, true, "uint16"},
+
+		// Unknown type defaults to string
+		{"unknown", "unknown_type", false, false, "string"},
+		{"unknown nullable", "unknown_type", true, false, "*string"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := mysqlTypeToGo(tt.dataType, tt.nullable, tt.unsigned)
+			if got != tt.want {
+				t.Errorf("mysqlTypeToGo(%q, %v, %v) = %q, want %q", tt.dataType, tt.nullable, tt.unsigned, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPostgresTypeToGo(t *testing.T) {
+	tests := []struct {
+		name     string
+		dataType string
+		nullable bool
+		array    bool
+		want     string
+	}{
+		{"int2", "int2", false, false, "int16"},
+		{"int4", "int4", false, false, "int32"},
+		{"int4 nullable", "int4", true, false, "*int32"},
+		{"int8", "int8", false, false, "int64"},
+		{"float4", "float4", false, false, "float32"},
+		{"float8", "float8", false, false, "float64"},
+		{"numeric", "numeric", false, false, "float64"},
+		{"bool", "bool", false, false, "bool"},
+		{"bool nullable", "bool", true, false, "*bool"},
+		{"text", "text", false, false, "string"},
+		{"varchar nullable", "varchar", true, false, "*string"},
+		{"uuid", "uuid", false, false, "string"},
+		{"enum", "enum", false, false, "string"},
+		{"bytea", "bytea", false, false, "[]byte"},
+		{"bytea nullable", "bytea", true, false, "[]byte"},
+		{"jsonb", "jsonb", false, false, "json.RawMessage"},
+		{"json nullable", "json", true, false, "json.RawMessage"},
+		{"timestamptz", "timestamptz", false, false, "time.Time"},
+		{"timestamptz nullable", "timestamptz", true, false, "*time.Time"},
+		{"date", "date", false, false, "time.Time"},
+		{"interval", "interval", false, false, "string"},
+		{"int4 array", "int4", false, true, "pq.Int32Array"},
+		{"int8 array nullable", "int8", true, true, "pq.Int64Array"},
+		{"text array", "text", false, true, "pq.StringArray"},
+		{"uuid array", "uuid", false, true, "pq.StringArray"},
+		{"bool array", "bool", false, true, "pq.BoolArray"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := postgresTypeToGo(tt.dataType, tt.nullable, tt.array)
+			if got != tt.want {
+				t.Errorf("postgresTypeToGo(%q, %v, %v) = %q, want %q", tt.dataType, tt.nullable, tt.array, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSQLiteTypeToGo(t *testing.T) {
+	tests := []struct {
+		name     string
+		dataType string
+		nullable bool
+		want     string
+	}{
+		{"integer", "integer", false, "int64"},
+		{"integer nullable", "integer", true, "*int64"},
+		{"bigint", "bigint", false, "int64"},
+		{"unsigned big int", "unsigned big int", false, "int64"},
+		{"varchar", "varchar", false, "string"},
+		{"text nullable", "text", true, "*string"},
+		{"clob", "clob", false, "string"},
+		{"blob", "blob", false, "[]byte"},
+		{"blob nullable", "blob", true, "[]byte"},
+		{"no type", "", true, "any"},
+		{"real", "real", false, "float64"},
+		{"double precision", "double precision", false, "float64"},
+		{"float nullable", "float", true, "*float64"},
+		{"decimal", "decimal", false, "float64"},
+		{"numeric", "numeric", false, "float64"},
+		{"boolean", "boolean", false, "bool"},
+		{"datetime", "datetime", false, "time.Time"},
+		{"timestamp nullable", "timestamp", true, "*time.Time"},
+		{"date", "date", false, "time.Time"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := sqliteTypeToGo(tt.dataType, tt.nullable)
+			if got != tt.want {
+				t.Errorf("sqliteTypeToGo(%q, %v) = %q, want %q", tt.dataType, tt.nullable, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGoTypeDialect(t *testing.T) {
+	col := schema.Column{Name: "payload", DataType: "json"}
+
+	if got := goType(schema.MySQL, col); got != "string" {
+		t.Errorf("goType(MySQL) = %q, want %q", got, "string")
+	}
+	if got := goType("", col); got != "string" {
+		t.Errorf("goType(\"\") = %q, want %q", got, "string")
+	}
+	if got := goType(schema.Postgres, col); got != "json.RawMessage" {
+		t.Errorf("goType(Postgres) = %q, want %q", got, "json.RawMessage")
+	}
+	if got := goType(schema.SQLite, col); got != "float64" {
+		t.Errorf("goType(SQLite) = %q, want %q", got, "float64")
+	}
+}
+
+func TestImportPath(t *testing.T) {
+	tests := []struct {
+		goType string
+		want   []string
+	}{
+		{"string", nil},
+		{"[]byte", nil},
+		{"time.Time", []string{"time"}},
+		{"*time.Time", []string{"time"}},
+		{"json.RawMessage", []string{"encoding/json"}},
+		{"pq.StringArray", []string{"github.com/lib/pq"}},
+		{"sql.NullString", []string{"database/sql"}},
+		{"sql.Null[time.Time]", []string{"database/sql", "time"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.goType, func(t *testing.T) {
+			if got := importPaths(tt.goType); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("importPaths(%q) = %q, want %q", tt.goType, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNullable(t *testing.T) {
+	table := &schema.Table{
+		Name:    "users",
+		Dialect: schema.MySQL,
+		Columns: []schema.Column{
+			{Name: "id", DataType: "bigint"},
+			{Name: "name", DataType: "varchar", IsNullable: true},
+			{Name: "age", DataType: "int", IsNullable: true},
+			{Name: "flags", DataType: "tinyint", IsUnsigned: true, IsNullable: true},
+			{Name: "score", DataType: "float", IsNullable: true},
+			{Name: "created_at", DataType: "datetime", IsNullable: true},
+			{Name: "avatar", DataType: "blob", IsNullable: true},
+		},
+	}
+
+	tests := []struct {
+		nullable Nullable
+		want     []string
+	}{
+		{NullPointer, []string{"int64", "*string", "*int32", "*uint8", "*float32", "*time.Time", "[]byte"}},
+		{NullSQL, []string{"int64", "sql.NullString", "sql.NullInt32", "sql.NullByte", "sql.Null[float32]", "sql.NullTime", "[]byte"}},
+		{NullGeneric, []string{"int64", "sql.Null[string]", "sql.Null[int32]", "sql.Null[uint8]", "sql.Null[float32]", "sql.Null[time.Time]", "[]byte"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.nullable), func(t *testing.T) {
+			gen := New("models", "/tmp/output", WithNullable(tt.nullable))
+			for i, col := range table.Columns {
+				if got := gen.goType(table, col); got != tt.want[i] {
+					t.Errorf("goType(%s) = %q, want %q", col.Name, got, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestParseNullable(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    Nullable
+		wantErr bool
+	}{
+		{"", NullPointer, false},
+		{"pointer", NullPointer, false},
+		{"sql", NullSQL, false},
+		{"generic", NullGeneric, false},
+		{"nullable", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseNullable(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseNullable(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseNullable(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTypeOverrides(t *testing.T) {
+	gen := New("models", "/tmp/output", WithTypeOverrides(map[string]TypeOverride{
+		"JSON":    {Type: "json.RawMessage", Nullable: "json.RawMessage"},
+		"decimal": {Type: "decimal.Decimal", Import: "github.com/shopspring/decimal"},
+	}))
+	table := &schema.Table{
+		Name: "orders",
+		Columns: []schema.Column{
+			{Name: "id", DataType: "bigint"},
+			{Name: "total", DataType: "decimal"},
+			{Name: "discount", DataType: "decimal", IsNullable: true},
+			{Name: "meta", DataType: "json", IsNullable: true},
+		},
+	}
+
+	want := []string{"int64", "decimal.Decimal", "*decimal.Decimal", "json.RawMessage"}
+	for i, col := range table.Columns {
+		if got := gen.goType(table, col); got != want[i] {
+			t.Errorf("goType(%s) = %q, want %q", col.Name, got, want[i])
+		}
+	}
+
+	imports := gen.collectImports(table)
+	if wantImports := []string{"encoding/json", "github.com/shopspring/decimal"}; !reflect.DeepEqual(imports, wantImports) {
+		t.Errorf("collectImports() = %v, want %v", imports, wantImports)
+	}
+}
+
+func TestTypeOverridePrecedence(t *testing.T) {
+	gen := New("models", "/tmp/output", WithTypeOverrides(map[string]TypeOverride{
+		"Users.ID": {Type: "UserID"},
+		"*.id":     {Type: "ID"},
+		"*.*_uuid": {Type: "uuid.UUID", Import: "github.com/google/uuid", Nullable: "uuid.NullUUID"},
+		"char(36)": {Type: "uuid.UUID", Import: "github.com/google/uuid"},
+		"char":     {Type: "Code"},
+	}))
+	users := &schema.Table{Name: "users"}
+	orders := &schema.Table{Name: "orders"}
+
+	tests := []struct {
+		table *schema.Table
+		col   schema.Column
+		want  string
+	}{
+		{users, schema.Column{Name: "id", DataType: "bigint"}, "UserID"},
+		{orders, schema.Column{Name: "id", DataType: "bigint"}, "ID"},
+		{orders, schema.Column{Name: "user_id", DataType: "bigint"}, "int64"},
+		{orders, schema.Column{Name: "order_uuid", DataType: "binary", ColumnType: "binary(16)", IsNullable: true}, "uuid.NullUUID"},
+		{orders, schema.Column{Name: "ref", DataType: "char", ColumnType: "char(36)", IsNullable: true}, "*uuid.UUID"},
+		{orders, schema.Column{Name: "country", DataType: "char", ColumnType: "char(2)"}, "Code"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.table.Name+"."+tt.col.Name, func(t *testing.T) {
+			if got := gen.goType(tt.table, tt.col); got != tt.want {
+				t.Errorf("goType() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	table := &schema.Table{Name: "orders", Columns: []schema.Column{
+		{Name: "id", DataType: "bigint"},
+		{Name: "ref", DataType: "char", ColumnType: "char(36)"},
+	}}
+	if imports, want := gen.collectImports(table), []string{"github.com/google/uuid"}; !reflect.DeepEqual(imports, want) {
+		t.Errorf("collectImports() = %v, want %v", imports, want)
+	}
+}
+
+func TestParseDecimal(t *testing.T) {
+	tests := []struct {
+		strategy string
+		want     TypeOverride
+		wantErr  bool
+	}{
+		{"", TypeOverride{}, false},
+		{"float64", TypeOverride{}, false},
+		{"string", TypeOverride{Type: "string"}, false},
+		{"shopspring", TypeOverride{Type: "decimal.Decimal", Import: "github.com/shopspring/decimal", Nullable: "decimal.NullDecimal"}, false},
+		{"github.com/cockroachdb/apd/v3.Decimal", TypeOverride{Type: "apd.Decimal", Import: "github.com/cockroachdb/apd/v3"}, false},
+		{"example.com/go-money.Amount", TypeOverride{Type: "gomoney.Amount", Import: "example.com/go-money"}, false},
+		{"Money", TypeOverride{Type: "Money"}, false},
+		{"big decimal", TypeOverride{}, true},
+		{"example.com/money.", TypeOverride{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.strategy, func(t *testing.T) {
+			got, err := ParseDecimal(tt.strategy)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseDecimal() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseDecimal() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecimalStrategy(t *testing.T) {
+	shopspring, _ := ParseDecimal("shopspring")
+	table := &schema.Table{
+		Name:    "prices",
+		Dialect: schema.Postgres,
+		Columns: []schema.Column{
+			{Name: "amount", DataType: "numeric", Precision: 12, Scale: 2},
+			{Name: "discount", DataType: "numeric", IsNullable: true},
+			{Name: "history", DataType: "numeric", IsArray: true},
+			{Name: "ratio", DataType: "float8"},
+		},
+	}
+
+	gen := New("models", "/tmp/output", WithDecimal(shopspring))
+	want := []string{"decimal.Decimal", "decimal.NullDecimal", "pq.Float64Array", "float64"}
+	for i, col := range table.Columns {
+		if got := gen.goType(table, col); got != want[i] {
+			t.Errorf("goType(%s) = %q, want %q", col.Name, got, want[i])
+		}
+	}
+	imports := gen.collectImports(table)
+	if wantImports := []string{"github.com/lib/pq", "github.com/shopspring/decimal"}; !reflect.DeepEqual(imports, wantImports) {
+		t.Errorf("collectImports() = %v, want %v", imports, wantImports)
+	}
+
+	// Explicit type overrides win over the decimal strategy.
+	gen = New("models", "/tmp/output", WithDecimal(shopspring),
+		WithTypeOverrides(map[string]TypeOverride{"numeric": {Type: "string"}}))
+	if got := gen.goType(table, table.Columns[0]); got != "string" {
+		t.Errorf("goType() with override = %q, want %q", got, "string")
+	}
+
+	gen = New("models", "/tmp/output")
+	if got := gen.goType(table, table.Columns[0]); got != "float64" {
+		t.Errorf("goType() by default = %q, want %q", got, "float64")
+	}
+}
@@ -0,0 +1,826 @@
+package schema
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ParseDDLFiles parses CREATE TABLE statements from the given files. A path
+// that names a directory is expanded to every .sql file inside it.
+func ParseDDLFiles(paths ...string) ([]Table, error) {
+	var tables []Table
+	for _, path := range paths {
+		files, err := expandDDLPath(path)
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range files {
+			f, err := os.Open(file)
+			if err != nil {
+				return nil, fmt.Errorf("failed to open schema file: %w", err)
+			}
+			parsed, err := ParseDDL(f)
+			f.Close()
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", file, err)
+			}
+			tables = append(tables, parsed...)
+		}
+	}
+	linkForeignKeys(tables)
+	return tables, nil
+}
+
+func expandDDLPath(path string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat schema file: %w", err)
+	}
+	if !info.IsDir() {
+		return []string{path}, nil
+	}
+	files, err := filepath.Glob(filepath.Join(path, "*.sql"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list schema files: %w", err)
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// ParseDDL parses the CREATE TABLE statements in r, such as the output of
+// mysqldump --no-data, and returns the tables in the order they appear.
+// Statements other than CREATE TABLE are ignored.
+func ParseDDL(r io.Reader) ([]Table, error) {
+	src, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schema: %w", err)
+	}
+	tokens, err := lexDDL(string(src))
+	if err != nil {
+		return nil, err
+	}
+
+	var tables []Table
+	for _, stmt := range splitStatements(tokens) {
+		p := &ddlParser{tokens: stmt}
+		if !p.isCreateTable() {
+			continue
+		}
+		table, err := p.parseCreateTable()
+		if err != nil {
+			return nil, err
+		}
+		tables = append(tables, *table)
+	}
+	linkForeignKeys(tables)
+	return tables, nil
+}
+
+type tokenKind int
+
+const (
+	tokenWord tokenKind = iota
+	tokenIdent
+	tokenString
+	tokenNumber
+	tokenPunct
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	line int
+	// quote is the quote character of a quoted token.
+	quote byte
+}
+
+// is reports whether t is the given keyword or punctuation, ignoring case.
+// Quoted identifiers never match keywords.
+func (t token) is(s string) bool {
+	return (t.kind == tokenWord || t.kind == tokenPunct) && strings.EqualFold(t.text, s)
+}
+
+// isString reports whether t can be a string literal where the grammar
+// expects one. Outside ANSI_QUOTES mode MySQL reads "text" as a string,
+// which the lexer cannot tell from an identifier.
+func (t token) isString() bool {
+	return t.kind == tokenString || (t.kind == tokenIdent && t.quote == '"')
+}
+
+func lexDDL(src string) ([]token, error) {
+	var tokens []token
+	line := 1
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case c == '\n':
+			line++
+			i++
+		case c == ' ' || c == '\t' || c == '\r':
+			i++
+		case c == '#' || (c == '-' && strings.HasPrefix(src[i:], "--") &&
+			(i+2 == len(src) || isSpace(src[i+2]))):
+			for i < len(src) && src[i] != '\n' {
+				i++
+			}
+		case c == '/' && strings.HasPrefix(src[i:], "/*"):
+			end := strings.Index(src[i+2:], "*/")
+			if end < 0 {
+				return nil, fmt.Errorf("line %d: unterminated comment", line)
+			}
+			line += strings.Count(src[i:i+2+end], "\n")
+			i += end + 4
+		case c == '\'' || c == '"' || c == '`':
+			text, n, err := lexQuoted(src[i:], c)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", line, err)
+			}
+			kind := tokenString
+			if c == '`' || c == '"' {
+				kind = tokenIdent
+			}
+			tokens = append(tokens, token{kind: kind, text: text, line: line, quote: c})
+			line += strings.Count(src[i:i+n], "\n")
+			i += n
+		case c >= '0' && c <= '9':
+			start := i
+			for i < len(src) && (isWordByte(src[i]) || src[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenNumber, text: src[start:i], line: line})
+		case isWordByte(c):
+			start := i
+			for i < len(src) && isWordByte(src[i]) {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenWord, text: src[start:i], line: line})
+		default:
+			tokens = append(tokens, token{kind: tokenPunct, text: string(c), line: line})
+			i++
+		}
+	}
+	return tokens, nil
+}
+
+// lexQuoted reads a quoted string or identifier starting at s[0] and returns
+// its unescaped text and the number of bytes consumed.
+func lexQuoted(s string, quote byte) (string, int, error) {
+	var b strings.Builder
+	for i := 1; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == quote && i+1 < len(s) && s[i+1] == quote:
+			b.WriteByte(quote)
+			i++
+		case c == quote:
+			return b.String(), i + 1, nil
+		case c == '\\' && quote == '\'' && i+1 < len(s):
+			i++
+			switch s[i] {
+			case 'n':
+				b.WriteByte('\n')
+			case 't':
+				b.WriteByte('\t')
+			case 'r':
+				b.WriteByte('\r')
+			case '0':
+				b.WriteByte(0)
+			default:
+				b.WriteByte(s[i])
+			}
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return "", 0, fmt.Errorf("unterminated quoted string")
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\r' || c == '\n'
+}
+
+func isWordByte(c byte) bool {
+	return c == '_' || c == '$' ||
+		(c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9') || c >= 0x80
+}
+
+func splitStatements(tokens []token) [][]token {
+	var stmts [][]token
+	start := 0
+	for i, t := range tokens {
+		if t.is(";") {
+			if i > start {
+				stmts = append(stmts, tokens[start:i])
+			}
+			start = i + 1
+		}
+	}
+	if start < len(tokens) {
+		stmts = append(stmts, tokens[start:])
+	}
+	return stmts
+}
+
+type ddlParser struct {
+	tokens []token
+	pos    int
+	// constraint is the name given by a CONSTRAINT clause to the
+	// definition being parsed.
+	constraint string
+}
+
+func (p *ddlParser) peek() token {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return token{kind: tokenPunct}
+}
+
+func (p *ddlParser) next() token {
+	t := p.peek()
+	if p.pos < len(p.tokens) {
+		p.pos++
+	}
+	return t
+}
+
+func (p *ddlParser) done() bool {
+	return p.pos >= len(p.tokens)
+}
+
+// accept consumes the given sequence of keywords if the input starts with it.
+func (p *ddlParser) accept(words ...string) bool {
+	if p.pos+len(words) > len(p.tokens) {
+		return false
+	}
+	for i, w := range words {
+		if !p.tokens[p.pos+i].is(w) {
+			return false
+		}
+	}
+	p.pos += len(words)
+	return true
+}
+
+func (p *ddlParser) expect(s string) error {
+	t := p.next()
+	if !t.is(s) {
+		return p.errorf(t, "expected %q, found %q", s, t.text)
+	}
+	return nil
+}
+
+func (p *ddlParser) errorf(t token, format string, args ...any) error {
+	return fmt.Errorf("line %d: %s", t.line, fmt.Sprintf(format, args...))
+}
+
+func (p *ddlParser) isCreateTable() bool {
+	p.pos = 0
+	if !p.accept("CREATE") {
+		return false
+	}
+	p.accept("TEMPORARY")
+	ok := p.accept("TABLE")
+	p.pos = 0
+	return ok
+}
+
+// identifier reads a possibly qualified name and returns its last part.
+func (p *ddlParser) identifier() (string, error) {
+	t := p.next()
+	if t.kind != tokenWord && t.kind != tokenIdent {
+		return "", p.errorf(t, "expected identifier, found %q", t.text)
+	}
+	name := t.text
+	for p.accept(".") {
+		t = p.next()
+		if t.kind != tokenWord && t.kind != tokenIdent {
+			return "", p.errorf(t, "expected identifier after \".\", found %q", t.text)
+		}
+		name = t.text
+	}
+	return name, nil
+}
+
+// skipGroup skips a balanced parenthesized group, starting at "(".
+func (p *ddlParser) skipGroup() {
+	depth := 0
+	for !p.done() {
+		t := p.next()
+		switch {
+		case t.is("("):
+			depth++
+		case t.is(")"):
+			depth--
+			if depth == 0 {
+				return
+			}
+		}
+	}
+}
+
+// typeArgs reads the parenthesized arguments of a data type, if any, such
+// as the precision and scale of DECIMAL(10,2), returning the text of each.
+func (p *ddlParser) typeArgs() []string {
+	if !p.peek().is("(") {
+		return nil
+	}
+	p.next()
+	var args []string
+	var arg strings.Builder
+	depth := 1
+	for !p.done() {
+		t := p.next()
+		switch {
+		case t.is("("):
+			depth++
+		case t.is(")"):
+			depth--
+			if depth == 0 {
+				return append(args, arg.String())
+			}
+		case depth == 1 && t.is(","):
+			args = append(args, arg.String())
+			arg.Reset()
+			continue
+		}
+		arg.WriteString(t.text)
+	}
+	return args
+}
+
+// skipDefinition skips tokens up to the "," or ")" ending a definition.
+func (p *ddlParser) skipDefinition() {
+	for !p.done() && !p.peek().is(",") && !p.peek().is(")") {
+		if p.peek().is("(") {
+			p.skipGroup()
+			continue
+		}
+		p.next()
+	}
+}
+
+func (p *ddlParser) parseCreateTable() (*Table, error) {
+	p.accept("CREATE")
+	p.accept("TEMPORARY")
+	p.accept("TABLE")
+	p.accept("IF", "NOT", "EXISTS")
+
+	name, err := p.identifier()
+	if err != nil {
+		return nil, err
+	}
+	table := &Table{Name: name, Dialect: MySQL, Type: BaseTable}
+
+	if p.accept("LIKE") {
+		return nil, p.errorf(p.peek(), "CREATE TABLE %s LIKE is not supported", name)
+	}
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+
+	for {
+		if err := p.parseDefinition(table); err != nil {
+			return nil, fmt.Errorf("table %s: %w", name, err)
+		}
+		t := p.next()
+		if t.is(")") {
+			break
+		}
+		if !t.is(",") {
+			return nil, p.errorf(t, "table %s: expected \",\" or \")\", found %q", name, t.text)
+		}
+	}
+
+	// Table options such as ENGINE=InnoDB follow the definitions; only the
+	// comment is kept.
+	for !p.done() {
+		if !p.accept("COMMENT") {
+			p.next()
+			continue
+		}
+		p.accept("=")
+		if t := p.next(); t.isString() {
+			table.Comment = t.text
+		}
+	}
+
+	finishColumnKeys(table)
+	return table, nil
+}
+
+func (p *ddlParser) parseDefinition(table *Table) error {
+	t := p.peek()
+	switch {
+	case t.is("PRIMARY"):
+		p.accept("PRIMARY", "KEY")
+		return p.parseIndex(table, Index{Name: "PRIMARY", Primary: true, Unique: true})
+	case t.is("UNIQUE"):
+		p.next()
+		if !p.accept("KEY") {
+			p.accept("INDEX")
+		}
+		return p.parseIndex(table, Index{Unique: true})
+	case t.is("KEY"), t.is("INDEX"):
+		p.next()
+		return p.parseIndex(table, Index{})
+	case t.is("FULLTEXT"), t.is("SPATIAL"):
+		p.next()
+		if !p.accept("KEY") {
+			p.accept("INDEX")
+		}
+		return p.parseIndex(table, Index{Type: strings.ToUpper(t.text)})
+	case t.is("CONSTRAINT"):
+		p.next()
+		if !p.peek().is("PRIMARY") && !p.peek().is("UNIQUE") &&
+			!p.peek().is("FOREIGN") && !p.peek().is("CHECK") {
+			name, err := p.identifier()
+			if err != nil {
+				return err
+			}
+			p.constraint = name
+		}
+		err := p.parseDefinition(table)
+		p.constraint = ""
+		return err
+	case t.is("FOREIGN"):
+		p.accept("FOREIGN", "KEY")
+		return p.parseForeignKey(table)
+	case t.is("CHECK"):
+		p.skipDefinition()
+		return nil
+	}
+
+	col, err := p.parseColumn()
+	if err != nil {
+		return err
+	}
+	table.Columns = append(table.Columns, *col)
+	switch col.ColumnKey {
+	case "PRI":
+		addIndex(table, Index{Name: "PRIMARY", Columns: []string{col.Name}, Primary: true, Unique: true})
+	case "UNI":
+		addIndex(table, Index{Columns: []string{col.Name}, Unique: true})
+	}
+	return nil
+}
+
+// parseIndex reads the rest of an index definition following its keywords:
+// an optional name and USING clause, the column list and index options.
+func (p *ddlParser) parseIndex(table *Table, idx Index) error {
+	if !p.peek().is("(") && !p.peek().is("USING") {
+		name, err := p.identifier()
+		if err != nil {
+			return err
+		}
+		if !idx.Primary {
+			idx.Name = name
+		}
+	}
+	if p.accept("USING") {
+		idx.Type = strings.ToUpper(p.next().text)
+	}
+	cols, err := p.indexColumns()
+	if err != nil {
+		return err
+	}
+	idx.Columns = cols
+	for !p.done() && !p.peek().is(",") && !p.peek().is(")") {
+		if p.accept("USING") {
+			idx.Type = strings.ToUpper(p.next().text)
+			continue
+		}
+		p.next()
+	}
+	addIndex(table, idx)
+	return nil
+}
+
+// parseForeignKey reads a FOREIGN KEY definition following its keywords.
+// Unnamed keys are named <table>_ibfk_<n> as MySQL does.
+func (p *ddlParser) parseForeignKey(table *Table) error {
+	fk := ForeignKey{Name: p.constraint, Table: table.Name}
+	if !p.peek().is("(") {
+		// An index name, which MySQL uses as the constraint name when
+		// no CONSTRAINT clause is given.
+		name, err := p.identifier()
+		if err != nil {
+			return err
+		}
+		if fk.Name == "" {
+			fk.Name = name
+		}
+	}
+	cols, err := p.indexColumns()
+	if err != nil {
+		return err
+	}
+	fk.Columns = cols
+	if err := p.expect("REFERENCES"); err != nil {
+		return err
+	}
+	if fk.RefTable, err = p.identifier(); err != nil {
+		return err
+	}
+	if fk.RefColumns, err = p.indexColumns(); err != nil {
+		return err
+	}
+	if len(fk.RefColumns) != len(fk.Columns) {
+		return p.errorf(p.peek(), "foreign key has %d columns but references %d", len(fk.Columns), len(fk.RefColumns))
+	}
+	for !p.done() && !p.peek().is(",") && !p.peek().is(")") {
+		switch {
+		case p.accept("ON", "DELETE"):
+			fk.OnDelete = p.referentialAction()
+		case p.accept("ON", "UPDATE"):
+			fk.OnUpdate = p.referentialAction()
+		default:
+			p.next()
+		}
+	}
+	if fk.Name == "" {
+		fk.Name = fmt.Sprintf("%s_ibfk_%d", table.Name, len(table.ForeignKeys)+1)
+	}
+	table.ForeignKeys = append(table.ForeignKeys, fk)
+	return nil
+}
+
+func (p *ddlParser) referentialAction() string {
+	switch {
+	case p.accept("SET", "NULL"):
+		return "SET NULL"
+	case p.accept("SET", "DEFAULT"):
+		return "SET DEFAULT"
+	case p.accept("NO", "ACTION"):
+		return "NO ACTION"
+	default:
+		return strings.ToUpper(p.next().text)
+	}
+}
+
+// indexColumns reads a parenthesized index column list, dropping prefix
+// lengths and sort order.
+func (p *ddlParser) indexColumns() ([]string, error) {
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+	var cols []string
+	for {
+		if p.peek().is("(") {
+			// Functional key part; it names no column.
+			p.skipGroup()
+		} else {
+			name, err := p.identifier()
+			if err != nil {
+				return nil, err
+			}
+			cols = append(cols, name)
+			if p.peek().is("(") {
+				p.skipGroup()
+			}
+		}
+		p.accept("ASC")
+		p.accept("DESC")
+		t := p.next()
+		if t.is(")") {
+			return cols, nil
+		}
+		if !t.is(",") {
+			return nil, p.errorf(t, "expected \",\" or \")\" in index column list, found %q", t.text)
+		}
+	}
+}
+
+func (p *ddlParser) parseColumn() (*Column, error) {
+	name, err := p.identifier()
+	if err != nil {
+		return nil, err
+	}
+	col := &Column{Name: name, IsNullable: true}
+
+	typ := p.next()
+	if typ.kind != tokenWord {
+		return nil, p.errorf(typ, "column %s: expected data type, found %q", name, typ.text)
+	}
+	var extras []string
+	var zerofill bool
+	col.DataType = strings.ToLower(typ.text)
+	switch col.DataType {
+	case "bool", "boolean":
+		col.DataType = "tinyint"
+	case "dec", "fixed":
+		col.DataType = "decimal"
+	case "nchar":
+		col.DataType = "char"
+	case "nvarchar":
+		col.DataType = "varchar"
+	case "national":
+		col.DataType = strings.ToLower(p.next().text)
+		if col.DataType == "character" {
+			col.DataType = "char"
+		}
+	case "double":
+		p.accept("PRECISION")
+	case "long":
+		col.DataType = "mediumtext"
+		if p.accept("VARBINARY") {
+			col.DataType = "mediumblob"
+		} else {
+			p.accept("VARCHAR")
+		}
+	case "serial":
+		col.DataType = "bigint"
+		col.IsUnsigned = true
+		col.IsNullable = false
+		col.ColumnKey = "UNI"
+		extras = append(extras, "auto_increment")
+	}
+	p.accept("VARYING")
+	args := p.typeArgs()
+	if len(args) == 0 && (typ.is("BOOL") || typ.is("BOOLEAN") || col.DataType == "bit") {
+		// MySQL reports BOOL as tinyint(1) and BIT as bit(1).
+		args = []string{"1"}
+	}
+	if isDecimal(col.DataType) {
+		// MySQL defaults to DECIMAL(10,0).
+		col.Precision, col.Scale = 10, 0
+		if len(args) > 0 {
+			col.Precision, _ = strconv.Atoi(args[0])
+		}
+		if len(args) > 1 {
+			col.Scale, _ = strconv.Atoi(args[1])
+		}
+	}
+
+	for !p.done() && !p.peek().is(",") && !p.peek().is(")") {
+		switch {
+		case p.accept("UNSIGNED"):
+			col.IsUnsigned = true
+		case p.accept("ZEROFILL"):
+			col.IsUnsigned = true
+			zerofill = true
+		case p.accept("SIGNED"), p.accept("BINARY"):
+		case p.accept("NOT", "NULL"):
+			col.IsNullable = false
+		case p.accept("NULL"):
+			col.IsNullable = true
+		case p.accept("CHARACTER", "SET"), p.accept("CHARSET"), p.accept("COLLATE"):
+			p.next()
+		case p.accept("DEFAULT"):
+			if p.skipExpression() {
+				extras = append(extras, "DEFAULT_GENERATED")
+			}
+		case p.accept("ON", "UPDATE"):
+			expr := p.next().text
+			if p.peek().is("(") {
+				p.skipGroup()
+			}
+			extras = append(extras, "on update "+strings.ToUpper(expr))
+		case p.accept("AUTO_INCREMENT"):
+			extras = append(extras, "auto_increment")
+		case p.accept("PRIMARY", "KEY"), p.accept("KEY"):
+			col.ColumnKey = "PRI"
+			col.IsNullable = false
+		case p.accept("UNIQUE"):
+			p.accept("KEY")
+			if col.ColumnKey != "PRI" {
+				col.ColumnKey = "UNI"
+			}
+		case p.accept("COMMENT"):
+			t := p.next()
+			if !t.isString() {
+				return nil, p.errorf(t, "column %s: expected comment string, found %q", name, t.text)
+			}
+			col.Comment = t.text
+		case p.accept("GENERATED", "ALWAYS"), p.accept("AS"):
+			p.accept("AS")
+			if p.peek().is("(") {
+				p.skipGroup()
+			}
+			kind := "VIRTUAL"
+			if p.accept("STORED") {
+				kind = "STORED"
+			} else {
+				p.accept("VIRTUAL")
+			}
+			extras = append(extras, kind+" GENERATED")
+		case p.peek().is("("):
+			p.skipGroup()
+		default:
+			p.next()
+		}
+	}
+	col.Extra = strings.Join(extras, " ")
+	col.ColumnType = ddlColumnType(col.DataType, args, col.IsUnsigned, zerofill)
+	switch col.DataType {
+	case "enum":
+		col.EnumValues = args
+	case "set":
+		col.SetMembers = args
+	}
+	return col, nil
+}
+
+// ddlColumnType formats a column type the way MySQL reports COLUMN_TYPE,
+// such as decimal(10,2) or int(10) unsigned zerofill.
+func ddlColumnType(dataType string, args []string, unsigned, zerofill bool) string {
+	columnType := dataType
+	if isDecimal(dataType) && len(args) == 0 {
+		args = []string{"10", "0"}
+	}
+	if len(args) > 0 {
+		if dataType == "enum" || dataType == "set" {
+			quoted := make([]string, len(args))
+			for i, arg := range args {
+				quoted[i] = "'" + strings.ReplaceAll(arg, "'", "''") + "'"
+			}
+			args = quoted
+		}
+		columnType += "(" + strings.Join(args, ",") + ")"
+	}
+	if unsigned {
+		columnType += " unsigned"
+	}
+	if zerofill {
+		columnType += " zerofill"
+	}
+	return columnType
+}
+
+// skipExpression skips a DEFAULT value, which is a single literal,
+// identifier or function call, or a parenthesized expression. It reports
+// whether the default is computed, which MySQL marks as DEFAULT_GENERATED.
+func (p *ddlParser) skipExpression() bool {
+	if p.accept("-") || p.accept("+") {
+		p.next()
+		return false
+	}
+	if p.peek().is("(") {
+		p.skipGroup()
+		return true
+	}
+	t := p.next()
+	if t.kind != tokenWord {
+		return false
+	}
+	if p.peek().isString() {
+		// Introducer or typed literal such as _utf8mb4'x' or b'1'.
+		p.next()
+		return false
+	}
+	if p.peek().is("(") {
+		p.skipGroup()
+	}
+	return !t.is("NULL") && !t.is("TRUE") && !t.is("FALSE")
+}
+
+// addIndex appends idx to the table, naming an unnamed index after its
+// first column as MySQL does, and keeping the primary key first.
+func addIndex(table *Table, idx Index) {
+	if len(idx.Columns) == 0 {
+		return
+	}
+	if idx.Type == "" {
+		idx.Type = "BTREE"
+	}
+	if idx.Primary {
+		for _, name := range idx.Columns {
+			if col := table.Column(name); col != nil {
+				col.IsNullable = false
+			}
+		}
+		table.Indexes = append([]Index{idx}, table.Indexes...)
+		return
+	}
+	if idx.Name == "" {
+		idx.Name = idx.Columns[0]
+		for n := 2; table.Index(idx.Name) != nil; n++ {
+			idx.Name = fmt.Sprintf("%s_%d", idx.Columns[0], n)
+		}
+	}
+	table.Indexes = append(table.Indexes, idx)
+}
+
+// finishColumnKeys derives COLUMN_KEY values from the parsed indexes and
+// applies MySQL's rule that a UNIQUE NOT NULL column is reported as PRI
+// when the table has no explicit primary key.
+func finishColumnKeys(table *Table) {
+	applyIndexKeys(table)
+	for _, col := range table.Columns {
+		if col.ColumnKey == "PRI" {
+			return
+		}
+	}
+	for i := range table.Columns {
+		col := &table.Columns[i]
+		if col.ColumnKey == "UNI" && !col.IsNullable {
+			col.ColumnKey = "PRI"
+			return
+		}
+	}
+}
@@ -0,0 +1,308 @@
+package schema
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const testDump = `
+-- MySQL dump 10.13  Distrib 8.0.36, for Linux (x86_64)
+/*!40101 SET @OLD_CHARACTER_SET_CLIENT=@@CHARACTER_SET_CLIENT */;
+DROP TABLE IF EXISTS ` + "`user_accounts`" + `;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+CREATE TABLE ` + "`user_accounts`" + ` (
+  ` + "`id`" + ` bigint unsigned NOT NULL AUTO_INCREMENT COMMENT 'Primary key',
+  ` + "`username`" + ` varchar(100) COLLATE utf8mb4_bin NOT NULL,
+  ` + "`email`" + ` varchar(255) NOT NULL DEFAULT '',
+  ` + "`avatar_url`" + ` varchar(500) DEFAULT NULL COMMENT 'it''s a URL; maybe',
+  ` + "`balance`" + ` decimal(10,2) NOT NULL DEFAULT '0.00',
+  ` + "`score`" + ` int(11) DEFAULT '-1',
+  ` + "`status`" + ` enum('active','banned') NOT NULL DEFAULT 'active',
+  ` + "`created_at`" + ` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  ` + "`updated_at`" + ` timestamp NULL DEFAULT NULL ON UPDATE CURRENT_TIMESTAMP,
+  ` + "`full_name`" + ` varchar(200) GENERATED ALWAYS AS (concat(username, ' ', email)) VIRTUAL,
+  PRIMARY KEY (` + "`id`" + `),
+  UNIQUE KEY ` + "`uk_email`" + ` (` + "`email`" + `),
+  KEY ` + "`idx_name`" + ` (` + "`username`" + `(10), ` + "`created_at`" + ` DESC) USING BTREE,
+  CONSTRAINT ` + "`fk_x`" + ` FOREIGN KEY (` + "`score`" + `) REFERENCES ` + "`scores`" + ` (` + "`id`" + `) ON DELETE CASCADE
+) ENGINE=InnoDB AUTO_INCREMENT=42 DEFAULT CHARSET=utf8mb4 COMMENT='Accounts';
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+CREATE TABLE IF NOT EXISTS app.tags (
+  name VARCHAR(50) NOT NULL UNIQUE,
+  flag BOOLEAN,
+  perms SET('read','Write') NOT NULL DEFAULT '',
+  hidden BIT NOT NULL DEFAULT b'0'
+);
+`
+
+func TestParseDDL(t *testing.T) {
+	tables, err := ParseDDL(strings.NewReader(testDump))
+	if err != nil {
+		t.Fatalf("ParseDDL() error = %v", err)
+	}
+	if len(tables) != 2 {
+		t.Fatalf("len(tables) = %d, want 2", len(tables))
+	}
+
+	accounts := tables[0]
+	if accounts.Name != "user_accounts" {
+		t.Errorf("Name = %q, want %q", accounts.Name, "user_accounts")
+	}
+	if accounts.Comment != "Accounts" {
+		t.Errorf("Comment = %q, want %q", accounts.Comment, "Accounts")
+	}
+
+	want := []Column{
+		{Name: "id", DataType: "bigint", ColumnType: "bigint unsigned", IsUnsigned: true, ColumnKey: "PRI", Extra: "auto_increment", Comment: "Primary key"},
+		{Name: "username", DataType: "varchar", ColumnType: "varchar(100)", ColumnKey: "MUL"},
+		{Name: "email", DataType: "varchar", ColumnType: "varchar(255)", ColumnKey: "UNI"},
+		{Name: "avatar_url", DataType: "varchar", ColumnType: "varchar(500)", IsNullable: true, Comment: "it's a URL; maybe"},
+		{Name: "balance", DataType: "decimal", ColumnType: "decimal(10,2)", Precision: 10, Scale: 2},
+		{Name: "score", DataType: "int", ColumnType: "int(11)", IsNullable: true},
+		{Name: "status", DataType: "enum", ColumnType: "enum('active','banned')", EnumValues: []string{"active", "banned"}},
+		{Name: "created_at", DataType: "datetime", ColumnType: "datetime", Extra: "DEFAULT_GENERATED"},
+		{Name: "updated_at", DataType: "timestamp", ColumnType: "timestamp", IsNullable: true, Extra: "on update CURRENT_TIMESTAMP"},
+		{Name: "full_name", DataType: "varchar", ColumnType: "varchar(200)", IsNullable: true, Extra: "VIRTUAL GENERATED"},
+	}
+	if len(accounts.Columns) != len(want) {
+		t.Fatalf("len(Columns) = %d, want %d", len(accounts.Columns), len(want))
+	}
+	for i, w := range want {
+		if got := accounts.Columns[i]; !reflect.DeepEqual(got, w) {
+			t.Errorf("Columns[%d] = %+v, want %+v", i, got, w)
+		}
+	}
+
+	wantIndexes := []Index{
+		{Name: "PRIMARY", Columns: []string{"id"}, Unique: true, Primary: true, Type: "BTREE"},
+		{Name: "uk_email", Columns: []string{"email"}, Unique: true, Type: "BTREE"},
+		{Name: "idx_name", Columns: []string{"username", "created_at"}, Type: "BTREE"},
+	}
+	if !reflect.DeepEqual(accounts.Indexes, wantIndexes) {
+		t.Errorf("Indexes = %+v, want %+v", accounts.Indexes, wantIndexes)
+	}
+
+	tags := tables[1]
+	if tags.Name != "tags" {
+		t.Errorf("Name = %q, want %q", tags.Name, "tags")
+	}
+	if got := tags.Columns[0]; got.ColumnKey != "PRI" || got.IsNullable {
+		t.Errorf("unique not null column without primary key = %+v, want PRI", got)
+	}
+	if got := tags.Columns[1]; got.DataType != "tinyint" || got.ColumnType != "tinyint(1)" || !got.IsNullable {
+		t.Errorf("BOOLEAN column = %+v, want nullable tinyint(1)", got)
+	}
+	if got := tags.Columns[2]; got.ColumnType != "set('read','Write')" || !reflect.DeepEqual(got.SetMembers, []string{"read", "Write"}) {
+		t.Errorf("Columns[2] = %+v, want set('read','Write') with its members", got)
+	}
+	if got := tags.Columns[3]; got.DataType != "bit" || got.ColumnType != "bit(1)" {
+		t.Errorf("BIT column = %+v, want bit(1)", got)
+	}
+	wantIndexes = []Index{{Name: "name", Columns: []string{"name"}, Unique: true, Type: "BTREE"}}
+	if !reflect.DeepEqual(tags.Indexes, wantIndexes) {
+		t.Errorf("Indexes = %+v, want %+v", tags.Indexes, wantIndexes)
+	}
+}
+
+func TestParseDDLIndexes(t *testing.T) {
+	ddl := `CREATE TABLE memberships (
+		group_id int NOT NULL,
+		user_id int NOT NULL,
+		role varchar(20),
+		bio text,
+		UNIQUE (role, user_id),
+		KEY (role),
+		FULLTEXT KEY ft_bio (bio),
+		INDEX idx_hash USING HASH (user_id),
+		CONSTRAINT pk PRIMARY KEY (user_id, group_id)
+	)`
+	tables, err := ParseDDL(strings.NewReader(ddl))
+	if err != nil {
+		t.Fatalf("ParseDDL() error = %v", err)
+	}
+	table := tables[0]
+
+	want := []Index{
+		{Name: "PRIMARY", Columns: []string{"user_id", "group_id"}, Unique: true, Primary: true, Type: "BTREE"},
+		{Name: "role", Columns: []string{"role", "user_id"}, Unique: true, Type: "BTREE"},
+		{Name: "role_2", Columns: []string{"role"}, Type: "BTREE"},
+		{Name: "ft_bio", Columns: []string{"bio"}, Type: "FULLTEXT"},
+		{Name: "idx_hash", Columns: []string{"user_id"}, Type: "HASH"},
+	}
+	if !reflect.DeepEqual(table.Indexes, want) {
+		t.Errorf("Indexes = %+v, want %+v", table.Indexes, want)
+	}
+
+	var keys []string
+	for _, col := range table.Columns {
+		keys = append(keys, col.ColumnKey)
+	}
+	if want := []string{"PRI", "PRI", "MUL", "MUL"}; !reflect.DeepEqual(keys, want) {
+		t.Errorf("column keys = %v, want %v", keys, want)
+	}
+
+	var pk []string
+	for _, col := range table.PrimaryKey() {
+		pk = append(pk, col.Name)
+	}
+	if want := []string{"user_id", "group_id"}; !reflect.DeepEqual(pk, want) {
+		t.Errorf("PrimaryKey() = %v, want %v", pk, want)
+	}
+}
+
+func TestParseDDLDecimal(t *testing.T) {
+	ddl := `CREATE TABLE prices (
+		a decimal(12,4) NOT NULL,
+		b DECIMAL(8),
+		c numeric,
+		d fixed(5, 1),
+		e int(11)
+	)`
+	tables, err := ParseDDL(strings.NewReader(ddl))
+	if err != nil {
+		t.Fatalf("ParseDDL() error = %v", err)
+	}
+
+	want := [][2]int{{12, 4}, {8, 0}, {10, 0}, {5, 1}, {0, 0}}
+	for i, col := range tables[0].Columns {
+		if got := [2]int{col.Precision, col.Scale}; got != want[i] {
+			t.Errorf("column %s precision, scale = %v, want %v", col.Name, got, want[i])
+		}
+	}
+}
+
+func TestParseDDLDoubleQuotedStrings(t *testing.T) {
+	ddl := `CREATE TABLE notes (
+		id int NOT NULL COMMENT "Note ""id""",
+		title varchar(50) NOT NULL DEFAULT "untitled" COMMENT 'Title',
+		body text DEFAULT _utf8mb4"" COMMENT "it's the body"
+	) ENGINE=InnoDB COMMENT="Notes"`
+	tables, err := ParseDDL(strings.NewReader(ddl))
+	if err != nil {
+		t.Fatalf("ParseDDL() error = %v", err)
+	}
+
+	notes := tables[0]
+	if notes.Comment != "Notes" {
+		t.Errorf("Comment = %q, want %q", notes.Comment, "Notes")
+	}
+	want := []struct{ comment, extra string }{
+		{`Note "id"`, ""},
+		{"Title", ""},
+		{"it's the body", ""},
+	}
+	for i, w := range want {
+		col := notes.Columns[i]
+		if col.Comment != w.comment || col.Extra != w.extra {
+			t.Errorf("column %s comment, extra = %q, %q, want %q, %q", col.Name, col.Comment, col.Extra, w.comment, w.extra)
+		}
+	}
+}
+
+func TestParseDDLErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		ddl  string
+	}{
+		{"unterminated string", "CREATE TABLE t (a int COMMENT 'x);"},
+		{"unterminated comment", "/* CREATE TABLE t (a int);"},
+		{"missing column list", "CREATE TABLE t;"},
+		{"unclosed column list", "CREATE TABLE t (a int"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseDDL(strings.NewReader(tt.ddl)); err == nil {
+				t.Error("ParseDDL() error = nil, want error")
+			}
+		})
+	}
+}
+
+func TestParseDDLIgnoresOtherStatements(t *testing.T) {
+	ddl := `
+SET NAMES utf8mb4;
+INSERT INTO t VALUES ('CREATE TABLE x (a int);');
+CREATE VIEW v AS SELECT 1;
+`
+	tables, err := ParseDDL(strings.NewReader(ddl))
+	if err != nil {
+		t.Fatalf("ParseDDL() error = %v", err)
+	}
+	if len(tables) != 0 {
+		t.Errorf("len(tables) = %d, want 0", len(tables))
+	}
+}
+
+func TestParseDDLFiles(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "sqlgen_test")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	files := map[string]string{
+		"01_users.sql": "CREATE TABLE users (id int PRIMARY KEY);",
+		"02_posts.sql": "CREATE TABLE posts (id int PRIMARY KEY);",
+		"notes.txt":    "CREATE TABLE ignored (id int);",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+
+	tables, err := ParseDDLFiles(tmpDir)
+	if err != nil {
+		t.Fatalf("ParseDDLFiles() error = %v", err)
+	}
+	var names []string
+	for _, table := range tables {
+		names = append(names, table.Name)
+	}
+	if got := strings.Join(names, ","); got != "users,posts" {
+		t.Errorf("tables = %q, want %q", got, "users,posts")
+	}
+
+	if _, err := ParseDDLFiles(filepath.Join(tmpDir, "missing.sql")); err == nil {
+		t.Error("ParseDDLFiles() with missing file error = nil, want error")
+	}
+}
+
+func TestParseDDLForeignKeys(t *testing.T) {
+	ddl := `
+CREATE TABLE users (id bigint PRIMARY KEY);
+CREATE TABLE orders (
+	id bigint PRIMARY KEY,
+	user_id bigint NOT NULL,
+	parent_id bigint,
+	CONSTRAINT fk_orders_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE ON UPDATE NO ACTION,
+	FOREIGN KEY (parent_id) REFERENCES orders (id) ON DELETE SET NULL
+);`
+	tables, err := ParseDDL(strings.NewReader(ddl))
+	if err != nil {
+		t.Fatalf("ParseDDL() error = %v", err)
+	}
+	users, orders := tables[0], tables[1]
+
+	byUser := ForeignKey{Name: "fk_orders_user", Table: "orders", Columns: []string{"user_id"}, RefTable: "users", RefColumns: []string{"id"}, OnUpdate: "NO ACTION", OnDelete: "CASCADE"}
+	byParent := ForeignKey{Name: "orders_ibfk_2", Table: "orders", Columns: []string{"parent_id"}, RefTable: "orders", RefColumns: []string{"id"}, OnDelete: "SET NULL"}
+
+	if want := []ForeignKey{byUser, byParent}; !reflect.DeepEqual(orders.ForeignKeys, want) {
+		t.Errorf("orders ForeignKeys = %+v, want %+v", orders.ForeignKeys, want)
+	}
+	if want := []ForeignKey{byParent}; !reflect.DeepEqual(orders.ReferencedBy, want) {
+		t.Errorf("orders ReferencedBy = %+v, want %+v", orders.ReferencedBy, want)
+	}
+	if want := []ForeignKey{byUser}; !reflect.DeepEqual(users.ReferencedBy, want) {
+		t.Errorf("users ReferencedBy = %+v, want %+v", users.ReferencedBy, want)
+	}
+
+	if _, err := ParseDDL(strings.NewReader("CREATE TABLE t (a int, FOREIGN KEY (a) REFERENCES u (x, y));")); err == nil {
+		t.Error("ParseDDL() with mismatched foreign key columns error = nil, want error")
+	}
+}
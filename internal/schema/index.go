@@ -0,0 +1,128 @@
+package schema
+
+import "strings"
+
+// Column returns the column with the given name, or nil. Names are compared
+// case-insensitively, as MySQL does.
+func (t *Table) Column(name string) *Column {
+	for i := range t.Columns {
+		if strings.EqualFold(t.Columns[i].Name, name) {
+			return &t.Columns[i]
+		}
+	}
+	return nil
+}
+
+// IsView reports whether the table is a view, whose rows cannot be written.
+func (t *Table) IsView() bool {
+	return t.Type == View
+}
+
+// Index returns the index with the given name, or nil.
+func (t *Table) Index(name string) *Index {
+	for i := range t.Indexes {
+		if strings.EqualFold(t.Indexes[i].Name, name) {
+			return &t.Indexes[i]
+		}
+	}
+	return nil
+}
+
+// PrimaryKey returns the primary key columns in key order. Tables read
+// without index information fall back to the columns whose ColumnKey is PRI,
+// in table order.
+func (t *Table) PrimaryKey() []Column {
+	var cols []Column
+	for _, idx := range t.Indexes {
+		if !idx.Primary {
+			continue
+		}
+		for _, name := range idx.Columns {
+			if col := t.Column(name); col != nil {
+				cols = append(cols, *col)
+			}
+		}
+		return cols
+	}
+	for _, col := range t.Columns {
+		if col.ColumnKey == "PRI" {
+			cols = append(cols, col)
+		}
+	}
+	return cols
+}
+
+// applyIndexKeys sets each column's ColumnKey from the table's indexes the
+// way information_schema.COLUMNS reports COLUMN_KEY: PRI for primary key
+// columns, UNI for a single-column unique index and MUL for the first
+// column of any other index.
+func applyIndexKeys(table *Table) {
+	for i := range table.Columns {
+		table.Columns[i].ColumnKey = ""
+	}
+	for _, idx := range table.Indexes {
+		if !idx.Primary {
+			continue
+		}
+		for _, name := range idx.Columns {
+			if col := table.Column(name); col != nil {
+				col.ColumnKey = "PRI"
+			}
+		}
+	}
+	for _, idx := range table.Indexes {
+		if idx.Primary || len(idx.Columns) == 0 {
+			continue
+		}
+		col := table.Column(idx.Columns[0])
+		if col == nil {
+			continue
+		}
+		switch {
+		case idx.Unique && len(idx.Columns) == 1 && col.ColumnKey != "PRI":
+			col.ColumnKey = "UNI"
+		case col.ColumnKey == "":
+			col.ColumnKey = "MUL"
+		}
+	}
+}
+
+// addForeignKeys files each foreign key under ForeignKeys if it is declared
+// on table and under ReferencedBy if it references table. A self-reference
+// is filed under both.
+func addForeignKeys(table *Table, fks []ForeignKey) {
+	for _, fk := range fks {
+		if fk.Table == table.Name {
+			table.ForeignKeys = append(table.ForeignKeys, fk)
+		}
+		if fk.RefTable == table.Name {
+			table.ReferencedBy = append(table.ReferencedBy, fk)
+		}
+	}
+}
+
+// linkForeignKeys fills in ReferencedBy on each table from the ForeignKeys
+// of all tables, for sources that only describe outgoing keys. Keys already
+// present are kept.
+func linkForeignKeys(tables []Table) {
+	for i := range tables {
+		for _, fk := range tables[i].ForeignKeys {
+			for j := range tables {
+				ref := &tables[j]
+				if ref.Name != fk.RefTable || hasForeignKey(ref.ReferencedBy, fk) {
+					continue
+				}
+				ref.ReferencedBy = append(ref.ReferencedBy, fk)
+			}
+		}
+	}
+}
+
+func hasForeignKey(fks []ForeignKey, fk ForeignKey) bool {
+	for _, f := range fks {
+		if f.Table == fk.Table && f.Name == fk.Name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,104 @@
+package schema
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTablePrimaryKey(t *testing.T) {
+	tests := []struct {
+		name  string
+		table Table
+		want  []string
+	}{
+		{
+			name: "index order",
+			table: Table{
+				Columns: []Column{
+					{Name: "group_id", ColumnKey: "PRI"},
+					{Name: "user_id", ColumnKey: "PRI"},
+				},
+				Indexes: []Index{
+					{Name: "PRIMARY", Columns: []string{"user_id", "group_id"}, Primary: true, Unique: true},
+				},
+			},
+			want: []string{"user_id", "group_id"},
+		},
+		{
+			name: "column key fallback",
+			table: Table{
+				Columns: []Column{
+					{Name: "id", ColumnKey: "PRI"},
+					{Name: "email", ColumnKey: "UNI"},
+				},
+			},
+			want: []string{"id"},
+		},
+		{
+			name: "no primary key",
+			table: Table{
+				Columns: []Column{{Name: "email", ColumnKey: "UNI"}},
+				Indexes: []Index{{Name: "email", Columns: []string{"email"}, Unique: true}},
+			},
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, col := range tt.table.PrimaryKey() {
+				got = append(got, col.Name)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("PrimaryKey() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTableColumnAndIndex(t *testing.T) {
+	table := Table{
+		Columns: []Column{{Name: "Email"}},
+		Indexes: []Index{{Name: "uk_email", Columns: []string{"Email"}}},
+	}
+
+	if col := table.Column("email"); col == nil || col.Name != "Email" {
+		t.Errorf("Column(%q) = %v, want Email", "email", col)
+	}
+	if col := table.Column("missing"); col != nil {
+		t.Errorf("Column(%q) = %v, want nil", "missing", col)
+	}
+	if idx := table.Index("UK_EMAIL"); idx == nil || idx.Name != "uk_email" {
+		t.Errorf("Index(%q) = %v, want uk_email", "UK_EMAIL", idx)
+	}
+}
+
+func TestApplyIndexKeys(t *testing.T) {
+	table := Table{
+		Columns: []Column{
+			{Name: "id", ColumnKey: "MUL"},
+			{Name: "email"},
+			{Name: "first"},
+			{Name: "last"},
+			{Name: "org_id"},
+		},
+		Indexes: []Index{
+			{Name: "idx_email_prefix", Columns: []string{"email"}},
+			{Name: "PRIMARY", Columns: []string{"id"}, Primary: true, Unique: true},
+			{Name: "uk_email", Columns: []string{"email"}, Unique: true},
+			{Name: "uk_name", Columns: []string{"first", "last"}, Unique: true},
+			{Name: "idx_org", Columns: []string{"org_id", "id"}},
+		},
+	}
+
+	applyIndexKeys(&table)
+
+	var got []string
+	for _, col := range table.Columns {
+		got = append(got, col.ColumnKey)
+	}
+	if want := []string{"PRI", "UNI", "MUL", "", "MUL"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("column keys = %v, want %v", got, want)
+	}
+}
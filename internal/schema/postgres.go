@@ -0,0 +1,297 @@
+package schema
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+// PostgresReader is the PostgreSQL Source. Its database argument names a
+// schema within the connected database; an empty one selects "public".
+type PostgresReader struct {
+	db *sql.DB
+}
+
+func NewPostgresReader(dsn string) (*PostgresReader, error) {
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to ping database: %w", err)
+	}
+	return &PostgresReader{db: db}, nil
+}
+
+func (r *PostgresReader) Close() error {
+	return r.db.Close()
+}
+
+func postgresSchema(database string) string {
+	if database == "" {
+		return "public"
+	}
+	return database
+}
+
+func (r *PostgresReader) GetTables(database string) ([]string, error) {
+	query := `
+		SELECT table_name
+		FROM information_schema.tables
+		WHERE table_schema = $1
+		ORDER BY table_name
+	`
+	return queryNames(r.db, query, postgresSchema(database))
+}
+
+func (r *PostgresReader) GetViews(database string) ([]string, error) {
+	query := `
+		SELECT table_name
+		FROM information_schema.tables
+		WHERE table_schema = $1 AND table_type = 'VIEW'
+		ORDER BY table_name
+	`
+	return queryNames(r.db, query, postgresSchema(database))
+}
+
+func (r *PostgresReader) GetTableSchema(database, tableName string) (*Table, error) {
+	schemaName := postgresSchema(database)
+
+	// udt_name already resolves domains to their base type. Arrays report
+	// data_type ARRAY and an element type reachable through typelem.
+	query := `
+		SELECT
+			c.column_name,
+			c.data_type,
+			c.udt_name,
+			c.is_nullable,
+			COALESCE(c.column_default, ''),
+			c.is_identity,
+			c.is_generated,
+			COALESCE(et.typtype, t.typtype, ''),
+			COALESCE(et.typname, ''),
+			COALESCE(col_description(
+				(quote_ident(c.table_schema) || '.' || quote_ident(c.table_name))::regclass,
+				c.ordinal_position), ''),
+			COALESCE(c.numeric_precision, 0),
+			COALESCE(c.numeric_scale, 0),
+			COALESCE(format_type(a.atttypid, a.atttypmod), ''),
+			ARRAY(SELECT e.enumlabel FROM pg_catalog.pg_enum e
+				WHERE e.enumtypid = COALESCE(et.oid, t.oid) ORDER BY e.enumsortorder)
+		FROM information_schema.columns c
+		LEFT JOIN pg_catalog.pg_namespace n ON n.nspname = c.udt_schema
+		LEFT JOIN pg_catalog.pg_type t ON t.typname = c.udt_name AND t.typnamespace = n.oid
+		LEFT JOIN pg_catalog.pg_type et ON et.oid = t.typelem AND t.typcategory = 'A'
+		LEFT JOIN pg_catalog.pg_attribute a
+			ON a.attrelid = (quote_ident(c.table_schema) || '.' || quote_ident(c.table_name))::regclass
+			AND a.attname = c.column_name
+		WHERE c.table_schema = $1 AND c.table_name = $2
+		ORDER BY c.ordinal_position
+	`
+	rows, err := r.db.Query(query, schemaName, tableName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query columns: %w", err)
+	}
+	defer rows.Close()
+
+	table := &Table{Name: tableName, Dialect: Postgres}
+	for rows.Next() {
+		var c postgresColumn
+		if err := rows.Scan(&c.name, &c.dataType, &c.udtName, &c.isNullable, &c.columnDefault,
+			&c.isIdentity, &c.isGenerated, &c.typType, &c.elemName, &c.comment, &c.precision, &c.scale, &c.columnType, pq.Array(&c.enumValues)); err != nil {
+			return nil, fmt.Errorf("failed to scan column: %w", err)
+		}
+		table.Columns = append(table.Columns, c.column())
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(table.Columns) == 0 {
+		return table, nil
+	}
+
+	if err := r.readTableInfo(schemaName, table); err != nil {
+		return nil, err
+	}
+	if err := r.readIndexes(schemaName, table); err != nil {
+		return nil, err
+	}
+	applyIndexKeys(table)
+	if err := r.readForeignKeys(schemaName, table); err != nil {
+		return nil, err
+	}
+	return table, nil
+}
+
+// readTableInfo reads whether the table is a view, plain or materialized,
+// the comment set on it with COMMENT ON, and the definition of a view.
+func (r *PostgresReader) readTableInfo(schemaName string, table *Table) error {
+	query := `
+		SELECT
+			c.relkind IN ('v', 'm'),
+			COALESCE(obj_description(c.oid, 'pg_class'), ''),
+			CASE WHEN c.relkind IN ('v', 'm') THEN pg_get_viewdef(c.oid, true) ELSE '' END
+		FROM pg_class c
+		WHERE c.oid = (quote_ident($1) || '.' || quote_ident($2))::regclass
+	`
+	var isView bool
+	if err := r.db.QueryRow(query, schemaName, table.Name).Scan(&isView, &table.Comment, &table.ViewDefinition); err != nil {
+		return fmt.Errorf("failed to query table info: %w", err)
+	}
+	table.Type = BaseTable
+	if isView {
+		table.Type = View
+		table.ViewDefinition = strings.TrimSpace(table.ViewDefinition)
+	}
+	return nil
+}
+
+// postgresActions maps pg_constraint action codes to referential actions.
+var postgresActions = map[string]string{
+	"a": "NO ACTION",
+	"r": "RESTRICT",
+	"c": "CASCADE",
+	"n": "SET NULL",
+	"d": "SET DEFAULT",
+}
+
+// readForeignKeys reads the foreign keys declared on the table and those
+// referencing it from tables in the same schema.
+func (r *PostgresReader) readForeignKeys(schemaName string, table *Table) error {
+	query := `
+		SELECT con.conname, src.relname, sa.attname, dst.relname, da.attname,
+			con.confupdtype, con.confdeltype
+		FROM pg_catalog.pg_constraint con
+		JOIN pg_catalog.pg_class src ON src.oid = con.conrelid
+		JOIN pg_catalog.pg_class dst ON dst.oid = con.confrelid
+		JOIN pg_catalog.pg_namespace n ON n.oid = src.relnamespace AND n.oid = dst.relnamespace
+		CROSS JOIN LATERAL unnest(con.conkey, con.confkey) WITH ORDINALITY AS k(src_att, dst_att, ord)
+		JOIN pg_catalog.pg_attribute sa ON sa.attrelid = con.conrelid AND sa.attnum = k.src_att
+		JOIN pg_catalog.pg_attribute da ON da.attrelid = con.confrelid AND da.attnum = k.dst_att
+		WHERE con.contype = 'f' AND n.nspname = $1 AND (src.relname = $2 OR dst.relname = $2)
+		ORDER BY src.relname, con.conname, k.ord
+	`
+	rows, err := r.db.Query(query, schemaName, table.Name)
+	if err != nil {
+		return fmt.Errorf("failed to query foreign keys: %w", err)
+	}
+	defer rows.Close()
+
+	var fks []ForeignKey
+	for rows.Next() {
+		var fk ForeignKey
+		var column, refColumn string
+		if err := rows.Scan(&fk.Name, &fk.Table, &column, &fk.RefTable, &refColumn, &fk.OnUpdate, &fk.OnDelete); err != nil {
+			return fmt.Errorf("failed to scan foreign key: %w", err)
+		}
+		fk.OnUpdate = postgresActions[fk.OnUpdate]
+		fk.OnDelete = postgresActions[fk.OnDelete]
+		n := len(fks)
+		if n == 0 || fks[n-1].Name != fk.Name || fks[n-1].Table != fk.Table {
+			fks = append(fks, fk)
+			n++
+		}
+		fks[n-1].Columns = append(fks[n-1].Columns, column)
+		fks[n-1].RefColumns = append(fks[n-1].RefColumns, refColumn)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	addForeignKeys(table, fks)
+	return nil
+}
+
+// readIndexes reads the table's indexes from pg_index, primary key first.
+// Expression parts and INCLUDE columns are left out of Index.Columns.
+func (r *PostgresReader) readIndexes(schemaName string, table *Table) error {
+	query := `
+		SELECT i.relname, ix.indisunique, ix.indisprimary, upper(am.amname), COALESCE(a.attname, '')
+		FROM pg_catalog.pg_class t
+		JOIN pg_catalog.pg_namespace n ON n.oid = t.relnamespace
+		JOIN pg_catalog.pg_index ix ON ix.indrelid = t.oid
+		JOIN pg_catalog.pg_class i ON i.oid = ix.indexrelid
+		JOIN pg_catalog.pg_am am ON am.oid = i.relam
+		CROSS JOIN LATERAL unnest(ix.indkey::int2[]) WITH ORDINALITY AS k(attnum, ord)
+		LEFT JOIN pg_catalog.pg_attribute a ON a.attrelid = t.oid AND a.attnum = k.attnum AND k.attnum > 0
+		WHERE n.nspname = $1 AND t.relname = $2 AND k.ord <= ix.indnkeyatts
+		ORDER BY ix.indisprimary DESC, i.relname, k.ord
+	`
+	rows, err := r.db.Query(query, schemaName, table.Name)
+	if err != nil {
+		return fmt.Errorf("failed to query indexes: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var idx Index
+		var column string
+		if err := rows.Scan(&idx.Name, &idx.Unique, &idx.Primary, &idx.Type, &column); err != nil {
+			return fmt.Errorf("failed to scan index: %w", err)
+		}
+		n := len(table.Indexes)
+		if n == 0 || table.Indexes[n-1].Name != idx.Name {
+			table.Indexes = append(table.Indexes, idx)
+			n++
+		}
+		if column != "" {
+			table.Indexes[n-1].Columns = append(table.Indexes[n-1].Columns, column)
+		}
+	}
+	return rows.Err()
+}
+
+// postgresColumn is a row of the column query before normalization.
+type postgresColumn struct {
+	name          string
+	dataType      string
+	udtName       string
+	isNullable    string
+	columnDefault string
+	isIdentity    string
+	isGenerated   string
+	typType       string
+	elemName      string
+	comment       string
+	precision     int
+	scale         int
+	columnType    string
+	enumValues    []string
+}
+
+// column converts the row into a Column. DataType holds the udt name, such
+// as int4, timestamptz or jsonb, or "enum" for enum types; arrays set
+// IsArray and hold their element type.
+func (c postgresColumn) column() Column {
+	col := Column{
+		Name:       c.name,
+		DataType:   c.udtName,
+		ColumnType: c.columnType,
+		IsNullable: c.isNullable == "YES",
+		Comment:    c.comment,
+	}
+	if c.dataType == "ARRAY" {
+		col.IsArray = true
+		col.DataType = strings.TrimPrefix(c.udtName, "_")
+		if c.elemName != "" {
+			col.DataType = c.elemName
+		}
+	}
+	if c.typType == "e" {
+		col.DataType = "enum"
+		col.EnumValues = c.enumValues
+	}
+	if isDecimal(col.DataType) && !col.IsArray {
+		col.Precision, col.Scale = c.precision, c.scale
+	}
+
+	switch {
+	case c.isIdentity == "YES", strings.HasPrefix(c.columnDefault, "nextval("):
+		col.Extra = "auto_increment"
+	case c.isGenerated == "ALWAYS":
+		col.Extra = "STORED GENERATED"
+	}
+	return col
+}
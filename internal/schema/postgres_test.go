@@ -0,0 +1,73 @@
+package schema
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPostgresColumn(t *testing.T) {
+	tests := []struct {
+		name string
+		row  postgresColumn
+		want Column
+	}{
+		{
+			name: "serial primary key",
+			row:  postgresColumn{name: "id", dataType: "integer", udtName: "int4", isNullable: "NO", columnDefault: "nextval('users_id_seq'::regclass)", isIdentity: "NO", isGenerated: "NEVER", typType: "b"},
+			want: Column{Name: "id", DataType: "int4", Extra: "auto_increment"},
+		},
+		{
+			name: "identity column",
+			row:  postgresColumn{name: "id", dataType: "bigint", udtName: "int8", isNullable: "NO", isIdentity: "YES", isGenerated: "NEVER", typType: "b"},
+			want: Column{Name: "id", DataType: "int8", Extra: "auto_increment"},
+		},
+		{
+			name: "nullable timestamptz with comment",
+			row:  postgresColumn{name: "deleted_at", dataType: "timestamp with time zone", udtName: "timestamptz", isNullable: "YES", isIdentity: "NO", isGenerated: "NEVER", typType: "b", comment: "Soft delete"},
+			want: Column{Name: "deleted_at", DataType: "timestamptz", IsNullable: true, Comment: "Soft delete"},
+		},
+		{
+			name: "text array",
+			row:  postgresColumn{name: "tags", dataType: "ARRAY", udtName: "_text", isNullable: "YES", isIdentity: "NO", isGenerated: "NEVER", typType: "b", elemName: "text"},
+			want: Column{Name: "tags", DataType: "text", IsNullable: true, IsArray: true},
+		},
+		{
+			name: "enum",
+			row:  postgresColumn{name: "mood", dataType: "USER-DEFINED", udtName: "mood", isNullable: "NO", isIdentity: "NO", isGenerated: "NEVER", typType: "e", enumValues: []string{"sad", "ok", "happy"}},
+			want: Column{Name: "mood", DataType: "enum", EnumValues: []string{"sad", "ok", "happy"}},
+		},
+		{
+			name: "generated column",
+			row:  postgresColumn{name: "total", dataType: "numeric", udtName: "numeric", isNullable: "YES", isIdentity: "NO", isGenerated: "ALWAYS", typType: "b"},
+			want: Column{Name: "total", DataType: "numeric", IsNullable: true, Extra: "STORED GENERATED"},
+		},
+		{
+			name: "numeric precision and scale",
+			row:  postgresColumn{name: "price", dataType: "numeric", udtName: "numeric", isNullable: "NO", isIdentity: "NO", isGenerated: "NEVER", typType: "b", precision: 12, scale: 4, columnType: "numeric(12,4)"},
+			want: Column{Name: "price", DataType: "numeric", ColumnType: "numeric(12,4)", Precision: 12, Scale: 4},
+		},
+		{
+			name: "precision of other types ignored",
+			row:  postgresColumn{name: "n", dataType: "integer", udtName: "int4", isNullable: "NO", isIdentity: "NO", isGenerated: "NEVER", typType: "b", precision: 32},
+			want: Column{Name: "n", DataType: "int4"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.row.column()
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("column() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPostgresSchema(t *testing.T) {
+	if got := postgresSchema(""); got != "public" {
+		t.Errorf("postgresSchema(%q) = %q, want %q", "", got, "public")
+	}
+	if got := postgresSchema("billing"); got != "billing" {
+		t.Errorf("postgresSchema(%q) = %q, want %q", "billing", got, "billing")
+	}
+}
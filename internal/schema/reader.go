@@ -2,49 +2,184 @@ package schema
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
 	"strings"
 
-	_ "github.com/go-sql-driver/mysql"
+	"github.com/go-sql-driver/mysql"
 )
 
 type Column struct {
-	Name       string
-	DataType   string
-	IsNullable bool
-	IsUnsigned bool
-	ColumnKey  string
-	Extra      string
-	Comment    string
+	Name       string `json:"name"`
+	DataType   string `json:"data_type"`
+	IsNullable bool   `json:"is_nullable,omitempty"`
+	IsUnsigned bool   `json:"is_unsigned,omitempty"`
+	ColumnKey  string `json:"column_key,omitempty"`
+	Extra      string `json:"extra,omitempty"`
+	Comment    string `json:"comment,omitempty"`
+	// IsArray marks a Postgres array column; DataType is its element type.
+	IsArray bool `json:"is_array,omitempty"`
+	// ColumnType is the full lowercase column type, with its arguments and
+	// modifiers, such as char(36) or int(10) unsigned.
+	ColumnType string `json:"column_type,omitempty"`
+	// EnumValues lists the allowed values of an ENUM column, in order.
+	EnumValues []string `json:"enum_values,omitempty"`
+	// SetMembers lists the allowed members of a SET column, in order.
+	SetMembers []string `json:"set_members,omitempty"`
+	// Precision and Scale are the total and fractional digits of a DECIMAL
+	// or NUMERIC column, or 0 when undeclared or another type.
+	Precision int `json:"precision,omitempty"`
+	Scale     int `json:"scale,omitempty"`
 }
 
+// quotedArgs returns the quoted string arguments of a column type such as
+// enum('a','b'), unquoted, or nil if it has none. Quotes within a value
+// are doubled or escaped with a backslash.
+func quotedArgs(columnType string) []string {
+	_, rest, ok := strings.Cut(columnType, "(")
+	if !ok {
+		return nil
+	}
+	var args []string
+	for {
+		rest = strings.TrimLeft(rest, " ,")
+		if !strings.HasPrefix(rest, "'") {
+			return args
+		}
+		var arg strings.Builder
+		i := 1
+		for i < len(rest) {
+			c := rest[i]
+			if c == '\'' {
+				if i+1 < len(rest) && rest[i+1] == '\'' {
+					arg.WriteByte('\'')
+					i += 2
+					continue
+				}
+				break
+			}
+			if c == '\\' && i+1 < len(rest) {
+				i++
+				c = rest[i]
+			}
+			arg.WriteByte(c)
+			i++
+		}
+		args = append(args, arg.String())
+		rest = rest[min(i+1, len(rest)):]
+	}
+}
+
+// isDecimal reports whether dataType is an exact numeric type.
+func isDecimal(dataType string) bool {
+	return dataType == "decimal" || dataType == "numeric"
+}
+
+// TableType is the kind of a table, as reported in TABLE_TYPE.
+type TableType string
+
+const (
+	BaseTable TableType = "BASE TABLE"
+	View      TableType = "VIEW"
+)
+
 type Table struct {
-	Name    string
-	Columns []Column
+	Name    string   `json:"name"`
+	Dialect Dialect  `json:"dialect,omitempty"`
+	Columns []Column `json:"columns"`
+	Comment string   `json:"comment,omitempty"`
+	// Type is BaseTable or View. An empty Type is a base table.
+	Type TableType `json:"type,omitempty"`
+	// ViewDefinition is the SELECT statement of a view.
+	ViewDefinition string `json:"view_definition,omitempty"`
+	// Indexes lists the table's indexes, primary key first.
+	Indexes []Index `json:"indexes,omitempty"`
+	// ForeignKeys lists the foreign keys declared on the table.
+	ForeignKeys []ForeignKey `json:"foreign_keys,omitempty"`
+	// ReferencedBy lists the foreign keys of other tables, or of the table
+	// itself, that reference it.
+	ReferencedBy []ForeignKey `json:"referenced_by,omitempty"`
 }
 
+// ForeignKey is a foreign key constraint from Columns of Table to
+// RefColumns of RefTable, with the column lists in matching order.
+type ForeignKey struct {
+	Name       string   `json:"name"`
+	Table      string   `json:"table"`
+	Columns    []string `json:"columns"`
+	RefTable   string   `json:"ref_table"`
+	RefColumns []string `json:"ref_columns"`
+	// OnUpdate and OnDelete are referential actions such as CASCADE.
+	OnUpdate string `json:"on_update,omitempty"`
+	OnDelete string `json:"on_delete,omitempty"`
+}
+
+// Index is an index or key constraint, with its columns in index order.
+type Index struct {
+	Name    string   `json:"name"`
+	Columns []string `json:"columns"`
+	Unique  bool     `json:"unique,omitempty"`
+	Primary bool     `json:"primary,omitempty"`
+	// Type is the index method, such as BTREE, HASH, FULLTEXT or GIN.
+	Type string `json:"type,omitempty"`
+}
+
+// Dialect names the SQL dialect whose type names a Table's columns use.
+type Dialect string
+
+const (
+	MySQL    Dialect = "mysql"
+	Postgres Dialect = "postgres"
+	SQLite   Dialect = "sqlite"
+)
+
+// Reader is the MySQL Source, reading information_schema.
 type Reader struct {
-	db *sql.DB
+	db       *sql.DB
+	database string
 }
 
 func NewReader(dsn string) (*Reader, error) {
+	cfg, err := mysql.ParseDSN(dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse dsn: %w", err)
+	}
 	db, err := sql.Open("mysql", dsn)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
 	if err := db.Ping(); err != nil {
+		db.Close()
 		return nil, fmt.Errorf("failed to ping database: %w", err)
 	}
-	return &Reader{db: db}, nil
+	return &Reader{db: db, database: cfg.DBName}, nil
 }
 
 func (r *Reader) Close() error {
 	return r.db.Close()
 }
 
+// schemaName returns database, or the database named in the DSN if empty.
+func (r *Reader) schemaName(database string) string {
+	if database == "" {
+		return r.database
+	}
+	return database
+}
+
 func (r *Reader) GetTables(database string) ([]string, error) {
 	query := `SELECT TABLE_NAME FROM information_schema.TABLES WHERE TABLE_SCHEMA = ?`
-	rows, err := r.db.Query(query, database)
+	return queryNames(r.db, query, r.schemaName(database))
+}
+
+func (r *Reader) GetViews(database string) ([]string, error) {
+	query := `SELECT TABLE_NAME FROM information_schema.TABLES WHERE TABLE_SCHEMA = ? AND TABLE_TYPE = 'VIEW'`
+	return queryNames(r.db, query, r.schemaName(database))
+}
+
+// queryNames runs query, which selects a single column of table names.
+func queryNames(db *sql.DB, query string, args ...any) ([]string, error) {
+	rows, err := db.Query(query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query tables: %w", err)
 	}
@@ -70,27 +205,168 @@ func (r *Reader) GetTableSchema(database, tableName string) (*Table, error) {
 			COLUMN_TYPE,
 			IFNULL(COLUMN_KEY, ''),
 			IFNULL(EXTRA, ''),
-			IFNULL(COLUMN_COMMENT, '')
+			IFNULL(COLUMN_COMMENT, ''),
+			IFNULL(NUMERIC_PRECISION, 0),
+			IFNULL(NUMERIC_SCALE, 0)
 		FROM information_schema.COLUMNS
 		WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ?
 		ORDER BY ORDINAL_POSITION
 	`
-	rows, err := r.db.Query(query, database, tableName)
+	rows, err := r.db.Query(query, r.schemaName(database), tableName)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query columns: %w", err)
 	}
 	defer rows.Close()
 
-	table := &Table{Name: tableName}
+	table := &Table{Name: tableName, Dialect: MySQL}
 	for rows.Next() {
 		var col Column
 		var isNullable, columnType string
-		if err := rows.Scan(&col.Name, &col.DataType, &isNullable, &columnType, &col.ColumnKey, &col.Extra, &col.Comment); err != nil {
+		var precision, scale int
+		if err := rows.Scan(&col.Name, &col.DataType, &isNullable, &columnType, &col.ColumnKey, &col.Extra, &col.Comment,
+			&precision, &scale); err != nil {
 			return nil, fmt.Errorf("failed to scan column: %w", err)
 		}
+		if isDecimal(col.DataType) {
+			col.Precision, col.Scale = precision, scale
+		}
 		col.IsNullable = isNullable == "YES"
-		col.IsUnsigned = strings.Contains(strings.ToLower(columnType), "unsigned")
+		col.ColumnType = strings.ToLower(columnType)
+		switch col.DataType {
+		case "enum":
+			col.EnumValues = quotedArgs(columnType)
+		case "set":
+			col.SetMembers = quotedArgs(columnType)
+		}
+		col.IsUnsigned = strings.Contains(col.ColumnType, "unsigned")
 		table.Columns = append(table.Columns, col)
 	}
-	return table, rows.Err()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := r.readTableInfo(database, table); err != nil {
+		return nil, err
+	}
+	if err := r.readIndexes(database, table); err != nil {
+		return nil, err
+	}
+	if err := r.readForeignKeys(database, table); err != nil {
+		return nil, err
+	}
+	return table, nil
+}
+
+// readTableInfo reads the table's type and comment, and the definition of a
+// view. MySQL reports the comment of every view as "VIEW", which is not kept.
+func (r *Reader) readTableInfo(database string, table *Table) error {
+	query := `
+		SELECT
+			t.TABLE_TYPE,
+			IF(t.TABLE_TYPE = 'BASE TABLE', IFNULL(t.TABLE_COMMENT, ''), ''),
+			IFNULL(v.VIEW_DEFINITION, '')
+		FROM information_schema.TABLES t
+		LEFT JOIN information_schema.VIEWS v
+			ON v.TABLE_SCHEMA = t.TABLE_SCHEMA AND v.TABLE_NAME = t.TABLE_NAME
+		WHERE t.TABLE_SCHEMA = ? AND t.TABLE_NAME = ?
+	`
+	var tableType string
+	err := r.db.QueryRow(query, r.schemaName(database), table.Name).Scan(&tableType, &table.Comment, &table.ViewDefinition)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("failed to query table info: %w", err)
+	}
+	table.Type = BaseTable
+	if strings.HasSuffix(tableType, "VIEW") {
+		table.Type = View
+	}
+	return nil
+}
+
+// readForeignKeys reads the foreign keys declared on the table and those
+// referencing it from other tables of the same database.
+func (r *Reader) readForeignKeys(database string, table *Table) error {
+	query := `
+		SELECT
+			kcu.CONSTRAINT_NAME,
+			kcu.TABLE_NAME,
+			kcu.COLUMN_NAME,
+			kcu.REFERENCED_TABLE_NAME,
+			kcu.REFERENCED_COLUMN_NAME,
+			rc.UPDATE_RULE,
+			rc.DELETE_RULE
+		FROM information_schema.KEY_COLUMN_USAGE kcu
+		JOIN information_schema.REFERENTIAL_CONSTRAINTS rc
+			ON rc.CONSTRAINT_SCHEMA = kcu.CONSTRAINT_SCHEMA
+			AND rc.CONSTRAINT_NAME = kcu.CONSTRAINT_NAME
+			AND rc.TABLE_NAME = kcu.TABLE_NAME
+		WHERE kcu.TABLE_SCHEMA = ? AND kcu.REFERENCED_TABLE_SCHEMA = kcu.TABLE_SCHEMA
+			AND (kcu.TABLE_NAME = ? OR kcu.REFERENCED_TABLE_NAME = ?)
+		ORDER BY kcu.TABLE_NAME, kcu.CONSTRAINT_NAME, kcu.ORDINAL_POSITION
+	`
+	rows, err := r.db.Query(query, r.schemaName(database), table.Name, table.Name)
+	if err != nil {
+		return fmt.Errorf("failed to query foreign keys: %w", err)
+	}
+	defer rows.Close()
+
+	var fks []ForeignKey
+	for rows.Next() {
+		var fk ForeignKey
+		var column, refColumn string
+		if err := rows.Scan(&fk.Name, &fk.Table, &column, &fk.RefTable, &refColumn, &fk.OnUpdate, &fk.OnDelete); err != nil {
+			return fmt.Errorf("failed to scan foreign key: %w", err)
+		}
+		n := len(fks)
+		if n == 0 || fks[n-1].Name != fk.Name || fks[n-1].Table != fk.Table {
+			fks = append(fks, fk)
+			n++
+		}
+		fks[n-1].Columns = append(fks[n-1].Columns, column)
+		fks[n-1].RefColumns = append(fks[n-1].RefColumns, refColumn)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	addForeignKeys(table, fks)
+	return nil
+}
+
+// readIndexes reads the table's indexes from information_schema.STATISTICS,
+// primary key first. Functional key parts have no column and are left out.
+func (r *Reader) readIndexes(database string, table *Table) error {
+	query := `
+		SELECT
+			INDEX_NAME,
+			NON_UNIQUE,
+			IFNULL(COLUMN_NAME, ''),
+			IFNULL(INDEX_TYPE, '')
+		FROM information_schema.STATISTICS
+		WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ?
+		ORDER BY INDEX_NAME = 'PRIMARY' DESC, INDEX_NAME, SEQ_IN_INDEX
+	`
+	rows, err := r.db.Query(query, r.schemaName(database), table.Name)
+	if err != nil {
+		return fmt.Errorf("failed to query indexes: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var idx Index
+		var nonUnique int
+		var column string
+		if err := rows.Scan(&idx.Name, &nonUnique, &column, &idx.Type); err != nil {
+			return fmt.Errorf("failed to scan index: %w", err)
+		}
+		idx.Unique = nonUnique == 0
+		idx.Primary = idx.Name == "PRIMARY"
+		n := len(table.Indexes)
+		if n == 0 || table.Indexes[n-1].Name != idx.Name {
+			table.Indexes = append(table.Indexes, idx)
+			n++
+		}
+		if column != "" {
+			table.Indexes[n-1].Columns = append(table.Indexes[n-1].Columns, column)
+		}
+	}
+	return rows.Err()
 }
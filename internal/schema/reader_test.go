@@ -1,6 +1,7 @@
 package schema
 
 import (
+	"reflect"
 	"testing"
 )
 
@@ -197,3 +198,22 @@ func TestColumnExtra(t *testing.T) {
 		})
 	}
 }
+
+func TestQuotedArgs(t *testing.T) {
+	tests := []struct {
+		columnType string
+		want       []string
+	}{
+		{"enum('active','banned')", []string{"active", "banned"}},
+		{"enum('It''s','a,b','', 'C:\\\\dir')", []string{"It's", "a,b", "", `C:\dir`}},
+		{"set('read','write')", []string{"read", "write"}},
+		{"varchar(255)", nil},
+		{"text", nil},
+	}
+
+	for _, tt := range tests {
+		if got := quotedArgs(tt.columnType); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("quotedArgs(%q) = %q, want %q", tt.columnType, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,287 @@
+package schema
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// SQLiteReader is the SQLite Source, reading sqlite_master and the
+// table_info, index_list, index_info and foreign_key_list pragmas. Its
+// database argument is ignored.
+type SQLiteReader struct {
+	db *sql.DB
+}
+
+func NewSQLiteReader(path string) (*SQLiteReader, error) {
+	// mode=ro keeps a mistyped path from creating an empty database. The
+	// path is escaped so that a '?', '#' or '%' in it is not read as part
+	// of the URI syntax.
+	dsn := (&url.URL{Scheme: "file", Opaque: url.PathEscape(path), RawQuery: "mode=ro"}).String()
+	db, err := sql.Open("sqlite3", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to ping database: %w", err)
+	}
+	return &SQLiteReader{db: db}, nil
+}
+
+func (r *SQLiteReader) Close() error {
+	return r.db.Close()
+}
+
+func (r *SQLiteReader) GetTables(database string) ([]string, error) {
+	query := `
+		SELECT name FROM sqlite_master
+		WHERE type IN ('table', 'view') AND name NOT LIKE 'sqlite_%'
+		ORDER BY name
+	`
+	return queryNames(r.db, query)
+}
+
+func (r *SQLiteReader) GetViews(database string) ([]string, error) {
+	query := `SELECT name FROM sqlite_master WHERE type = 'view' ORDER BY name`
+	return queryNames(r.db, query)
+}
+
+func (r *SQLiteReader) GetTableSchema(database, tableName string) (*Table, error) {
+	query := `SELECT name, type, "notnull", pk FROM pragma_table_info(?) ORDER BY cid`
+	rows, err := r.db.Query(query, tableName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query columns: %w", err)
+	}
+	defer rows.Close()
+
+	table := &Table{Name: tableName, Dialect: SQLite}
+	primary := make(map[int]string)
+	for rows.Next() {
+		var col Column
+		var declType string
+		var notNull, pk int
+		if err := rows.Scan(&col.Name, &declType, &notNull, &pk); err != nil {
+			return nil, fmt.Errorf("failed to scan column: %w", err)
+		}
+		col.DataType = sqliteDataType(declType)
+		col.ColumnType = strings.ToLower(declType)
+		if isDecimal(col.DataType) {
+			col.Precision, col.Scale = sqliteTypeArgs(declType)
+		}
+		col.IsUnsigned = strings.Contains(col.DataType, "unsigned")
+		col.IsNullable = notNull == 0 && pk == 0
+		if pk > 0 {
+			primary[pk] = col.Name
+		}
+		table.Columns = append(table.Columns, col)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// The primary key may have no index of its own, so it is built from
+	// table_info, whose pk field is the column's position in the key.
+	if len(primary) > 0 {
+		pkIndex := Index{Name: "PRIMARY", Primary: true, Unique: true, Type: "BTREE"}
+		for i := 1; i <= len(primary); i++ {
+			pkIndex.Columns = append(pkIndex.Columns, primary[i])
+		}
+		table.Indexes = append(table.Indexes, pkIndex)
+
+		// A single INTEGER PRIMARY KEY column aliases the rowid, which
+		// SQLite assigns automatically on insert.
+		if col := table.Column(primary[1]); len(primary) == 1 && col.DataType == "integer" {
+			col.Extra = "auto_increment"
+		}
+	}
+
+	if err := r.readTableInfo(table); err != nil {
+		return nil, err
+	}
+	if err := r.readIndexes(table); err != nil {
+		return nil, err
+	}
+	applyIndexKeys(table)
+	if err := r.readForeignKeys(table); err != nil {
+		return nil, err
+	}
+	return table, nil
+}
+
+// readTableInfo reads whether the table is a view and, for a view, the
+// SELECT statement of its CREATE VIEW.
+func (r *SQLiteReader) readTableInfo(table *Table) error {
+	var tableType, stmt string
+	query := `SELECT type, COALESCE(sql, '') FROM sqlite_master WHERE name = ?`
+	err := r.db.QueryRow(query, table.Name).Scan(&tableType, &stmt)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("failed to query table info: %w", err)
+	}
+	table.Type = BaseTable
+	if tableType == "view" {
+		table.Type = View
+		table.ViewDefinition = stmt
+		if m := createViewPattern.FindStringSubmatch(stmt); m != nil {
+			table.ViewDefinition = strings.TrimSpace(m[1])
+		}
+	}
+	return nil
+}
+
+// createViewPattern matches a CREATE VIEW statement, capturing its SELECT.
+var createViewPattern = regexp.MustCompile("(?is)^\\s*CREATE\\s+(?:TEMP(?:ORARY)?\\s+)?VIEW\\s+(?:IF\\s+NOT\\s+EXISTS\\s+)?" +
+	"(?:\"[^\"]*\"|`[^`]*`|\\[[^\\]]*\\]|[^\\s(]+)(?:\\s*\\([^)]*\\))?\\s+AS\\s+(.*)$")
+
+// readForeignKeys reads the foreign keys declared on the table and, by
+// scanning every table's foreign_key_list, those referencing it. SQLite
+// keys are unnamed, so they are named <table>_fk_<id>.
+func (r *SQLiteReader) readForeignKeys(table *Table) error {
+	query := `
+		SELECT m.name, f.id, f."from", f."to", f."table", f.on_update, f.on_delete
+		FROM sqlite_master m, pragma_foreign_key_list(m.name) f
+		WHERE m.type = 'table' AND (m.name = ? OR f."table" = ?)
+		ORDER BY m.name, f.id, f.seq
+	`
+	rows, err := r.db.Query(query, table.Name, table.Name)
+	if err != nil {
+		return fmt.Errorf("failed to query foreign keys: %w", err)
+	}
+	var fks []ForeignKey
+	for rows.Next() {
+		var fk ForeignKey
+		var id int
+		var column string
+		var refColumn sql.NullString
+		if err := rows.Scan(&fk.Table, &id, &column, &refColumn, &fk.RefTable, &fk.OnUpdate, &fk.OnDelete); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan foreign key: %w", err)
+		}
+		fk.Name = fmt.Sprintf("%s_fk_%d", fk.Table, id)
+		n := len(fks)
+		if n == 0 || fks[n-1].Name != fk.Name {
+			fks = append(fks, fk)
+			n++
+		}
+		fks[n-1].Columns = append(fks[n-1].Columns, column)
+		fks[n-1].RefColumns = append(fks[n-1].RefColumns, refColumn.String)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	// A key declared without referenced columns references the primary key.
+	for i := range fks {
+		if fks[i].RefColumns[0] != "" {
+			continue
+		}
+		pk, err := r.primaryKeyColumns(fks[i].RefTable)
+		if err != nil {
+			return err
+		}
+		if len(pk) == len(fks[i].Columns) {
+			fks[i].RefColumns = pk
+		}
+	}
+	addForeignKeys(table, fks)
+	return nil
+}
+
+func (r *SQLiteReader) primaryKeyColumns(tableName string) ([]string, error) {
+	rows, err := r.db.Query(`SELECT name FROM pragma_table_info(?) WHERE pk > 0 ORDER BY pk`, tableName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query primary key: %w", err)
+	}
+	defer rows.Close()
+
+	var cols []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("failed to scan primary key column: %w", err)
+		}
+		cols = append(cols, name)
+	}
+	return cols, rows.Err()
+}
+
+// readIndexes appends the table's indexes other than the primary key.
+func (r *SQLiteReader) readIndexes(table *Table) error {
+	query := `SELECT name, "unique" FROM pragma_index_list(?) WHERE origin <> 'pk' ORDER BY name`
+	rows, err := r.db.Query(query, table.Name)
+	if err != nil {
+		return fmt.Errorf("failed to query indexes: %w", err)
+	}
+	var indexes []Index
+	for rows.Next() {
+		idx := Index{Type: "BTREE"}
+		if err := rows.Scan(&idx.Name, &idx.Unique); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan index: %w", err)
+		}
+		indexes = append(indexes, idx)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, idx := range indexes {
+		cols, err := r.indexColumns(idx.Name)
+		if err != nil {
+			return err
+		}
+		idx.Columns = cols
+		table.Indexes = append(table.Indexes, idx)
+	}
+	return nil
+}
+
+func (r *SQLiteReader) indexColumns(index string) ([]string, error) {
+	rows, err := r.db.Query(`SELECT name FROM pragma_index_info(?) ORDER BY seqno`, index)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query index columns: %w", err)
+	}
+	defer rows.Close()
+
+	var cols []string
+	for rows.Next() {
+		// Expression index parts have a NULL name and are left out.
+		var name sql.NullString
+		if err := rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("failed to scan index column: %w", err)
+		}
+		if name.Valid {
+			cols = append(cols, name.String)
+		}
+	}
+	return cols, rows.Err()
+}
+
+// sqliteTypeArgs returns the numeric arguments of a declared type, such as
+// 10 and 2 for "DECIMAL(10, 2)", or zero for missing ones.
+func sqliteTypeArgs(declType string) (first, second int) {
+	_, args, ok := strings.Cut(declType, "(")
+	if !ok {
+		return 0, 0
+	}
+	args, _, _ = strings.Cut(args, ")")
+	a, b, _ := strings.Cut(args, ",")
+	first, _ = strconv.Atoi(strings.TrimSpace(a))
+	second, _ = strconv.Atoi(strings.TrimSpace(b))
+	return first, second
+}
+
+// sqliteDataType normalizes a declared column type, such as
+// "VARCHAR(255)", to its lower-case name without size arguments.
+func sqliteDataType(declType string) string {
+	name, _, _ := strings.Cut(declType, "(")
+	return strings.Join(strings.Fields(strings.ToLower(name)), " ")
+}
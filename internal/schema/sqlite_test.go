@@ -0,0 +1,236 @@
+package schema
+
+import (
+	"database/sql"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestSQLiteReader(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "sqlgen_test")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	path := filepath.Join(tmpDir, "app.db")
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	ddl := []string{
+		`CREATE TABLE users (
+			id INTEGER PRIMARY KEY,
+			email VARCHAR(255) NOT NULL UNIQUE,
+			name TEXT,
+			score UNSIGNED BIG INT,
+			created_at DATETIME NOT NULL,
+			data
+		)`,
+		`CREATE TABLE memberships (
+			user_id INTEGER NOT NULL REFERENCES users(id),
+			group_id INTEGER NOT NULL,
+			role TEXT,
+			invited_by INTEGER REFERENCES users ON DELETE SET NULL,
+			PRIMARY KEY (user_id, group_id)
+		)`,
+		`CREATE INDEX idx_memberships_role ON memberships (role)`,
+		`CREATE VIEW active_users AS SELECT id, email FROM users`,
+	}
+	for _, stmt := range ddl {
+		if _, err := db.Exec(stmt); err != nil {
+			t.Fatalf("failed to create schema: %v", err)
+		}
+	}
+	db.Close()
+
+	source, err := Open("sqlite://" + path)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	defer source.Close()
+
+	tables, err := source.GetTables("")
+	if err != nil {
+		t.Fatalf("GetTables() error = %v", err)
+	}
+	if want := []string{"active_users", "memberships", "users"}; !reflect.DeepEqual(tables, want) {
+		t.Errorf("GetTables() = %v, want %v", tables, want)
+	}
+
+	views, err := source.GetViews("")
+	if err != nil {
+		t.Fatalf("GetViews() error = %v", err)
+	}
+	if want := []string{"active_users"}; !reflect.DeepEqual(views, want) {
+		t.Errorf("GetViews() = %v, want %v", views, want)
+	}
+
+	users, err := source.GetTableSchema("", "users")
+	if err != nil {
+		t.Fatalf("GetTableSchema() error = %v", err)
+	}
+	want := &Table{
+		Name:    "users",
+		Dialect: SQLite,
+		Type:    BaseTable,
+		Columns: []Column{
+			{Name: "id", DataType: "integer", ColumnType: "integer", ColumnKey: "PRI", Extra: "auto_increment"},
+			{Name: "email", DataType: "varchar", ColumnType: "varchar(255)", ColumnKey: "UNI"},
+			{Name: "name", DataType: "text", ColumnType: "text", IsNullable: true},
+			{Name: "score", DataType: "unsigned big int", ColumnType: "unsigned big int", IsNullable: true, IsUnsigned: true},
+			{Name: "created_at", DataType: "datetime", ColumnType: "datetime"},
+			{Name: "data", DataType: "", IsNullable: true},
+		},
+		Indexes: []Index{
+			{Name: "PRIMARY", Columns: []string{"id"}, Unique: true, Primary: true, Type: "BTREE"},
+			{Name: "sqlite_autoindex_users_1", Columns: []string{"email"}, Unique: true, Type: "BTREE"},
+		},
+		ReferencedBy: []ForeignKey{
+			{Name: "memberships_fk_0", Table: "memberships", Columns: []string{"invited_by"}, RefTable: "users", RefColumns: []string{"id"}, OnUpdate: "NO ACTION", OnDelete: "SET NULL"},
+			{Name: "memberships_fk_1", Table: "memberships", Columns: []string{"user_id"}, RefTable: "users", RefColumns: []string{"id"}, OnUpdate: "NO ACTION", OnDelete: "NO ACTION"},
+		},
+	}
+	if !reflect.DeepEqual(users, want) {
+		t.Errorf("GetTableSchema(users) = %+v, want %+v", users, want)
+	}
+
+	view, err := source.GetTableSchema("", "active_users")
+	if err != nil {
+		t.Fatalf("GetTableSchema() error = %v", err)
+	}
+	if !view.IsView() || view.ViewDefinition != "SELECT id, email FROM users" {
+		t.Errorf("GetTableSchema(active_users) Type = %q, ViewDefinition = %q, want a view of SELECT id, email FROM users", view.Type, view.ViewDefinition)
+	}
+
+	memberships, err := source.GetTableSchema("", "memberships")
+	if err != nil {
+		t.Fatalf("GetTableSchema() error = %v", err)
+	}
+	var keys []string
+	for _, col := range memberships.Columns {
+		keys = append(keys, col.ColumnKey)
+		if col.Extra != "" {
+			t.Errorf("column %s Extra = %q, want none for composite primary key", col.Name, col.Extra)
+		}
+	}
+	if want := []string{"PRI", "PRI", "MUL", ""}; !reflect.DeepEqual(keys, want) {
+		t.Errorf("memberships column keys = %v, want %v", keys, want)
+	}
+	wantIndexes := []Index{
+		{Name: "PRIMARY", Columns: []string{"user_id", "group_id"}, Unique: true, Primary: true, Type: "BTREE"},
+		{Name: "idx_memberships_role", Columns: []string{"role"}, Type: "BTREE"},
+	}
+	if !reflect.DeepEqual(memberships.Indexes, wantIndexes) {
+		t.Errorf("memberships indexes = %+v, want %+v", memberships.Indexes, wantIndexes)
+	}
+	if !reflect.DeepEqual(memberships.ForeignKeys, users.ReferencedBy) {
+		t.Errorf("memberships foreign keys = %+v, want %+v", memberships.ForeignKeys, users.ReferencedBy)
+	}
+}
+
+func TestSQLiteReaderMissingFile(t *testing.T) {
+	if _, err := NewSQLiteReader(filepath.Join(os.TempDir(), "sqlgen_missing", "app.db")); err == nil {
+		t.Error("NewSQLiteReader() for missing file error = nil, want error")
+	}
+}
+
+func TestSQLiteReaderSpecialPath(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "sqlgen_test")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	// The database is created under a plain name, since an unescaped DSN
+	// would cut the path at its '?'.
+	plain := filepath.Join(tmpDir, "app.db")
+	db, err := sql.Open("sqlite3", plain)
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	if _, err := db.Exec(`CREATE TABLE users (id INTEGER PRIMARY KEY)`); err != nil {
+		t.Fatalf("failed to create table: %v", err)
+	}
+	db.Close()
+	path := filepath.Join(tmpDir, "a?b#c%20d.db")
+	if err := os.Rename(plain, path); err != nil {
+		t.Fatalf("failed to rename database: %v", err)
+	}
+
+	r, err := NewSQLiteReader(path)
+	if err != nil {
+		t.Fatalf("NewSQLiteReader() error = %v", err)
+	}
+	defer r.Close()
+	tables, err := r.GetTables("")
+	if err != nil {
+		t.Fatalf("GetTables() error = %v", err)
+	}
+	if !reflect.DeepEqual(tables, []string{"users"}) {
+		t.Errorf("GetTables() = %v, want [users]", tables)
+	}
+}
+
+func TestSQLiteTypeArgs(t *testing.T) {
+	tests := []struct {
+		declType      string
+		first, second int
+	}{
+		{"DECIMAL(10, 2)", 10, 2},
+		{"NUMERIC(8)", 8, 0},
+		{"DECIMAL", 0, 0},
+		{"DECIMAL(x)", 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.declType, func(t *testing.T) {
+			first, second := sqliteTypeArgs(tt.declType)
+			if first != tt.first || second != tt.second {
+				t.Errorf("sqliteTypeArgs(%q) = %d, %d, want %d, %d", tt.declType, first, second, tt.first, tt.second)
+			}
+		})
+	}
+}
+
+func TestSQLiteDataType(t *testing.T) {
+	tests := []struct {
+		declType string
+		want     string
+	}{
+		{"INTEGER", "integer"},
+		{"VARCHAR(255)", "varchar"},
+		{"DECIMAL(10, 2)", "decimal"},
+		{"UNSIGNED  BIG INT", "unsigned big int"},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.declType, func(t *testing.T) {
+			if got := sqliteDataType(tt.declType); got != tt.want {
+				t.Errorf("sqliteDataType(%q) = %q, want %q", tt.declType, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCreateViewPattern(t *testing.T) {
+	tests := []struct {
+		stmt string
+		want string
+	}{
+		{"CREATE VIEW v AS SELECT 1", "SELECT 1"},
+		{"create temp view if not exists \"my view\" as\n  select a\n  from t", "select a\n  from t"},
+		{"CREATE VIEW main.v(a, b) AS SELECT x, y FROM t", "SELECT x, y FROM t"},
+		{"CREATE VIEW [alias table] AS SELECT 1 AS one", "SELECT 1 AS one"},
+	}
+
+	for _, tt := range tests {
+		m := createViewPattern.FindStringSubmatch(tt.stmt)
+		if m == nil || m[1] != tt.want {
+			t.Errorf("createViewPattern on %q = %q, want %q", tt.stmt, m, tt.want)
+		}
+	}
+}